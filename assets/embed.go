@@ -2,8 +2,16 @@ package assets
 
 import "embed"
 
-//go:embed *.flf
+//go:generate go run gen_index.go
+
+//go:embed *.flf *.flc
 var FontFS embed.FS
 
 //go:embed fonts.yaml
 var FontsYAML []byte
+
+// FontIndex describes every font in FontFS, so fonts can be listed without
+// parsing them. It is generated by go generate; see gen_index.go.
+//
+//go:embed index.json
+var FontIndex []byte
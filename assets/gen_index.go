@@ -0,0 +1,35 @@
+//go:build ignore
+
+// gen_index writes index.json, the metadata of every embedded font, so fig
+// can list fonts without parsing them. Run it with go generate after adding,
+// removing or editing fonts or fonts.yaml.
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/phantompunk/fig/assets"
+	"github.com/phantompunk/fig/internal/font"
+)
+
+func main() {
+	tags, err := font.LoadTagMap(assets.FontsYAML)
+	if err != nil {
+		log.Fatal(err)
+	}
+	infos, err := font.BuildIndex(assets.FontFS, ".", tags)
+	if err != nil {
+		log.Fatal(err)
+	}
+	out, err := os.Create("index.json")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := font.WriteIndex(out, infos); err != nil {
+		log.Fatal(err)
+	}
+	if err := out.Close(); err != nil {
+		log.Fatal(err)
+	}
+}
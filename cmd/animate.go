@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+	"github.com/phantompunk/fig/internal/anim"
+	"github.com/phantompunk/fig/internal/export"
+	"github.com/phantompunk/fig/internal/input"
+	"github.com/phantompunk/fig/internal/render"
+	"github.com/spf13/cobra"
+)
+
+type animateFlags struct {
+	font     string
+	effect   string
+	cast     string
+	fps      int
+	duration time.Duration
+	cols     int
+	rows     int
+	loop     bool
+}
+
+func animateCmd() *cobra.Command {
+	var flags animateFlags
+
+	cmd := &cobra.Command{
+		Use:   "animate [text]",
+		Short: "Play typewriter, marquee, bounce or blink effects on rendered text",
+		Args:  cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAnimate(cmd, args, flags)
+		},
+	}
+
+	cmd.Flags().StringVarP(&flags.font, "font", "f", "standard", "Specify a font, default is standard")
+	cmd.Flags().StringVarP(&flags.effect, "effect", "e", "typewriter", "Animation effect: typewriter, marquee, bounce or blink")
+	cmd.Flags().StringVar(&flags.cast, "cast", "", "Write an asciicast v2 recording to this file instead of playing")
+	cmd.Flags().IntVar(&flags.fps, "fps", anim.DefaultFPS, "Frames per second")
+	cmd.Flags().DurationVar(&flags.duration, "duration", 0, "Total animation length, 0 for the effect's natural length")
+	cmd.Flags().IntVar(&flags.cols, "cols", 0, "Screen width in columns, 0 to detect")
+	cmd.Flags().IntVar(&flags.rows, "rows", 0, "Screen height in rows, 0 to detect")
+	cmd.Flags().BoolVar(&flags.loop, "loop", false, "Repeat the animation until interrupted")
+
+	return cmd
+}
+
+func runAnimate(cmd *cobra.Command, args []string, flags animateFlags) error {
+	effect, err := anim.Lookup(flags.effect)
+	if err != nil {
+		return err
+	}
+	if flags.fps <= 0 {
+		return fmt.Errorf("--fps must be positive")
+	}
+
+	msg, err := input.Resolve(args).Read()
+	if err != nil {
+		return err
+	}
+	if len(msg) == 0 {
+		return fmt.Errorf("nothing to animate: pass text as arguments or on stdin")
+	}
+
+	engine := newEngine(cmd)
+	layout, err := engine.Layout(msg, render.RenderOptions{FontName: flags.font})
+	if err != nil {
+		return err
+	}
+
+	cols, rows := screenSize()
+	if flags.cols > 0 {
+		cols = flags.cols
+	}
+	if flags.rows > 0 {
+		rows = flags.rows
+	}
+	rows = max(rows, len(layout.Lines))
+
+	frames := effect(layout, anim.Options{Width: cols, Height: rows, FPS: flags.fps, Duration: flags.duration})
+
+	if flags.cast == "" {
+		return anim.Play(cmd.Context(), cmd.OutOrStdout(), frames, flags.loop)
+	}
+
+	f, err := os.Create(flags.cast)
+	if err != nil {
+		return err
+	}
+	hdr := export.CastHeader{Width: cols, Height: rows, Title: msg, Timestamp: time.Now()}
+	if err := export.WriteCast(f, hdr, frames); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// screenSize returns the terminal size, falling back to 80x24 when stdout is
+// not a terminal.
+func screenSize() (int, int) {
+	w, h, err := term.GetSize(os.Stdout.Fd())
+	if err != nil || w <= 0 || h <= 0 {
+		return 80, 24
+	}
+	return w, h
+}
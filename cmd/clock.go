@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/phantompunk/fig/internal/anim"
+	"github.com/phantompunk/fig/internal/render"
+	"github.com/spf13/cobra"
+)
+
+// tickInterval is how often clocks poll the time and terminal size. Redraws
+// only happen when either changes.
+const tickInterval = 100 * time.Millisecond
+
+func clockCmd() *cobra.Command {
+	var fontName, layout string
+
+	cmd := &cobra.Command{
+		Use:   "clock",
+		Short: "Show a live clock in big letters",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			t := newTicker(cmd, fontName, func(now time.Time) (string, bool) {
+				return now.Format(layout), false
+			})
+			return t.Run(cmd.Context(), cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().StringVarP(&fontName, "font", "f", "standard", "Specify a font, default is standard")
+	cmd.Flags().StringVar(&layout, "format", "15:04:05", "Time format using Go reference time layout")
+
+	return cmd
+}
+
+func countdownCmd() *cobra.Command {
+	var fontName, message string
+
+	cmd := &cobra.Command{
+		Use:   "countdown <duration>",
+		Short: "Show a big ticking countdown timer",
+		Example: `  fig countdown 5m
+  fig countdown 1h30m --message "Break's over"`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			d, err := time.ParseDuration(args[0])
+			if err != nil {
+				return fmt.Errorf("invalid duration %q: %w", args[0], err)
+			}
+			if d <= 0 {
+				return fmt.Errorf("duration must be positive")
+			}
+
+			deadline := time.Now().Add(d)
+			t := newTicker(cmd, fontName, func(now time.Time) (string, bool) {
+				left := deadline.Sub(now)
+				if left <= 0 {
+					return message, true
+				}
+				return formatRemaining(left), false
+			})
+			return t.Run(cmd.Context(), cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().StringVarP(&fontName, "font", "f", "standard", "Specify a font, default is standard")
+	cmd.Flags().StringVarP(&message, "message", "m", "Time's up!", "Message shown when the countdown completes")
+
+	return cmd
+}
+
+// newTicker returns a ticker that renders text in the named font, centered
+// in the terminal.
+func newTicker(cmd *cobra.Command, fontName string, text func(time.Time) (string, bool)) *anim.Ticker {
+	engine := newEngine(cmd)
+	return &anim.Ticker{
+		Render: func(s string) (*render.Layout, error) {
+			return engine.Layout(s, render.RenderOptions{FontName: fontName})
+		},
+		Text:     text,
+		Size:     screenSize,
+		Interval: tickInterval,
+	}
+}
+
+// formatRemaining formats a countdown as MM:SS, or H:MM:SS from an hour up.
+// Partial seconds round up so the timer reads 00:01 until it completes.
+func formatRemaining(d time.Duration) string {
+	secs := int((d + time.Second - 1) / time.Second)
+	h, m, s := secs/3600, secs/60%60, secs%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%02d:%02d", m, s)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/phantompunk/fig/internal/font"
+	"github.com/phantompunk/fig/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+type designFlags struct {
+	out    string
+	height int
+	text   string
+}
+
+func designCmd() *cobra.Command {
+	var flags designFlags
+
+	cmd := &cobra.Command{
+		Use:   "design <font|file.flf>",
+		Short: "Edit a font's glyphs in an interactive grid editor",
+		Long: `Edit a font's glyphs in an interactive grid editor with a live preview.
+
+A bundled or installed font is saved as <name>.flf in the current directory
+unless --out is given; a font file is saved in place. Naming a file that does
+not exist starts a blank font of --height rows.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, path, err := designFont(cmd, args[0], flags)
+			if err != nil {
+				return err
+			}
+			if flags.out != "" {
+				path = flags.out
+			}
+			return tui.Design(f, path, flags.text)
+		},
+	}
+
+	cmd.Flags().StringVarP(&flags.out, "out", "o", "", "Save the font to this file")
+	cmd.Flags().IntVar(&flags.height, "height", 6, "Height of a new font, in rows")
+	cmd.Flags().StringVarP(&flags.text, "text", "t", "", "Sample text for the preview (default: the font name)")
+
+	return cmd
+}
+
+// designFont returns an editable copy of the font named by ref and the path
+// to save it to, or a blank font when ref is a file that does not exist yet.
+func designFont(cmd *cobra.Command, ref string, flags designFlags) (*font.Font, string, error) {
+	if font.IsPath(ref) {
+		path := strings.TrimPrefix(ref, "file://")
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			if flags.height < 1 {
+				return nil, "", errors.New("--height must be at least 1")
+			}
+			name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+			return font.Blank(name, flags.height), path, nil
+		}
+		f, err := font.LoadFile(path)
+		if err != nil {
+			return nil, "", err
+		}
+		return f, path, nil
+	}
+
+	f, err := newEngine(cmd).Font(ref)
+	if err != nil {
+		return nil, "", err
+	}
+	return f.Clone(), f.Name() + ".flf", nil
+}
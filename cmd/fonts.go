@@ -0,0 +1,569 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"github.com/phantompunk/fig/assets"
+	"github.com/phantompunk/fig/internal/font"
+	"github.com/phantompunk/fig/internal/render"
+	"github.com/spf13/cobra"
+)
+
+func fontsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fonts",
+		Short: "List, inspect, preview, lint, subset, convert, import and install fonts, and find dupes",
+	}
+
+	cmd.AddCommand(
+		fontsListCmd(),
+		fontsInfoCmd(),
+		fontsShowCmd(),
+		fontsLintCmd(),
+		fontsDupesCmd(),
+		fontsSubsetCmd(),
+		fontsConvertCmd(),
+		fontsImportCmd(),
+		fontsInstallCmd(),
+	)
+
+	return cmd
+}
+
+type listOptions struct {
+	tag       string
+	maxHeight int
+	json      bool
+	plain     bool
+	all       bool
+}
+
+// fontEntry is one row of `fig fonts list`.
+type fontEntry struct {
+	Name    string      `json:"name"`
+	Aliases []string    `json:"aliases,omitempty"`
+	Format  font.Format `json:"format"`
+	Height  int         `json:"height"`
+	Tags    []string    `json:"tags"`
+	Source  string      `json:"source"`
+}
+
+func fontsListCmd() *cobra.Command {
+	var opts listOptions
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List available fonts with their format, height, tags and source",
+		Long: `List available fonts with their format, height, tags and source. Aliases,
+names listed in fonts.yaml for another font and fonts identical to another
+one, are shown next to the font they stand for instead of on their own row.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return listFonts(cmd, opts)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.tag, "tag", "t", "", "Only list fonts with this tag")
+	cmd.Flags().IntVar(&opts.maxHeight, "max-height", 0, "Only list fonts at most this many lines tall")
+	cmd.Flags().BoolVar(&opts.json, "json", false, "Print the listing as JSON")
+	cmd.Flags().BoolVar(&opts.plain, "plain", false, "Print font names only, one per line")
+	cmd.Flags().BoolVar(&opts.all, "all", false, "List aliases on rows of their own")
+	cmd.MarkFlagsMutuallyExclusive("json", "plain")
+
+	return cmd
+}
+
+// listFonts prints the fonts matching opts, sorted by name. Fonts that fail
+// to parse are reported on stderr and skipped. Unless opts.all is set,
+// aliases are listed with the font they stand for.
+func listFonts(cmd *cobra.Command, opts listOptions) error {
+	w := cmd.OutOrStdout()
+	engine := newEngine(cmd)
+	names, err := engine.ListFonts()
+	if err != nil {
+		return err
+	}
+	slices.Sort(names)
+
+	tags, err := font.LoadTagMap(assets.FontsYAML)
+	if err != nil {
+		return err
+	}
+
+	fonts := make(map[string]*font.Font, len(names))
+	for _, name := range names {
+		f, err := engine.Font(name)
+		if err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "fig: skipping %s: %v\n", name, err)
+			continue
+		}
+		fonts[name] = f
+	}
+
+	canonical := make(map[string]string)
+	aliases := make(map[string][]string)
+	if !opts.all {
+		dupes, err := engine.FontDuplicates(1)
+		if err != nil {
+			return err
+		}
+		canonical = font.AutoAliases(dupes)
+		// A font loaded under another name is an alias from fonts.yaml.
+		for name, f := range fonts {
+			if f.Name() != name {
+				canonical[name] = f.Name()
+			}
+		}
+		for alias, name := range canonical {
+			aliases[name] = append(aliases[name], alias)
+		}
+	}
+
+	entries := make([]fontEntry, 0, len(names))
+	for _, name := range names {
+		f, ok := fonts[name]
+		if _, alias := canonical[name]; !ok || alias {
+			continue
+		}
+		if opts.tag != "" && !tags.HasTag(name, opts.tag) {
+			continue
+		}
+		if opts.maxHeight > 0 && f.Height() > opts.maxHeight {
+			continue
+		}
+		slices.Sort(aliases[name])
+		entries = append(entries, fontEntry{
+			Name:    name,
+			Aliases: aliases[name],
+			Format:  f.Format(),
+			Height:  f.Height(),
+			Tags:    append([]string{}, tags[name]...),
+			Source:  f.Source(),
+		})
+	}
+
+	switch {
+	case opts.json:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entries)
+	case opts.plain:
+		for _, e := range entries {
+			fmt.Fprintln(w, e.Name)
+		}
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tFORMAT\tHEIGHT\tTAGS\tALIASES\tSOURCE")
+	for _, e := range entries {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%s\n", e.Name, e.Format, e.Height, strings.Join(e.Tags, ","), strings.Join(e.Aliases, ","), e.Source)
+	}
+	return tw.Flush()
+}
+
+func fontsInfoCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "info <font>",
+		Short: "Show a font's header metadata, glyph coverage and comments",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := newEngine(cmd).Font(args[0])
+			if err != nil {
+				return err
+			}
+
+			direction := "left to right"
+			if f.PrintDirection() == 1 {
+				direction = "right to left"
+			}
+			fullLayout := "(derived from old layout)"
+			if f.HasFullLayout() {
+				fullLayout = fmt.Sprintf("%d %s", f.FullLayout(), describeRules(font.FullLayoutRules(f.FullLayout())))
+			}
+			ascii, tagged := 0, len(f.CodeTags())
+			for _, r := range f.Runes() {
+				if r >= 32 && r <= 126 {
+					ascii++
+				}
+			}
+
+			w := cmd.OutOrStdout()
+			fmt.Fprintf(w, "name:            %s\n", f.Name())
+			fmt.Fprintf(w, "source:          %s\n", f.Source())
+			fmt.Fprintf(w, "format:          %s\n", f.Format())
+			fmt.Fprintf(w, "hardblank:       %q\n", f.Hardblank())
+			fmt.Fprintf(w, "height:          %d\n", f.Height())
+			fmt.Fprintf(w, "baseline:        %d\n", f.Baseline())
+			fmt.Fprintf(w, "max length:      %d\n", f.MaxLength())
+			fmt.Fprintf(w, "old layout:      %d %s\n", f.OldLayout(), describeRules(font.OldLayoutRules(f.OldLayout())))
+			fmt.Fprintf(w, "full layout:     %s\n", fullLayout)
+			fmt.Fprintf(w, "print direction: %d (%s)\n", f.PrintDirection(), direction)
+			fmt.Fprintf(w, "code tags:       %d declared, %d read\n", f.CodeTagCount(), tagged)
+			fmt.Fprintf(w, "coverage:        %d glyphs (ASCII %d/95, German %d/7, tagged %d)\n",
+				len(f.Runes()), ascii, f.DeutschCount(), tagged)
+			fmt.Fprintf(w, "characters:      %s\n", runeRanges(f.Runes()))
+			if comments := f.Comments(); comments != "" {
+				fmt.Fprintf(w, "\n%s\n", comments)
+			}
+			return nil
+		},
+	}
+}
+
+// describeRules formats layout rule names as "(a, b)", or "(none)".
+func describeRules(names []string) string {
+	if len(names) == 0 {
+		return "(none)"
+	}
+	return "(" + strings.Join(names, ", ") + ")"
+}
+
+// runeRanges compresses sorted character codes into runs such as
+// "U+0020-U+007E U+00C4".
+func runeRanges(runes []rune) string {
+	code := func(r rune) string { return fmt.Sprintf("%U", r) }
+
+	var parts []string
+	for i := 0; i < len(runes); {
+		j := i
+		for j+1 < len(runes) && runes[j+1] == runes[j]+1 {
+			j++
+		}
+		if i == j {
+			parts = append(parts, code(runes[i]))
+		} else {
+			parts = append(parts, code(runes[i])+"-"+code(runes[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, " ")
+}
+
+func fontsShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <font> [text]",
+		Short: "Preview a font, rendering its name or the given text",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			text := args[0]
+			if len(args) > 1 {
+				text = strings.Join(args[1:], " ")
+			}
+			out, err := newEngine(cmd).Render(text, render.RenderOptions{FontName: args[0]})
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(cmd.OutOrStdout(), out)
+			return nil
+		},
+	}
+}
+
+func fontsLintCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "lint <file|font>...",
+		Short: "Check fonts against the FIGfont spec and report every problem as file:line",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			w := cmd.OutOrStdout()
+			total, failed := 0, 0
+			for _, arg := range args {
+				data, file, err := readFontSource(cmd, arg)
+				if err != nil {
+					return err
+				}
+				problems := font.Lint(data)
+				for _, p := range problems {
+					fmt.Fprintf(w, "%s:%d: %s\n", file, p.Line, p.Msg)
+				}
+				if len(problems) > 0 {
+					total += len(problems)
+					failed++
+				}
+			}
+			if total > 0 {
+				return fmt.Errorf("found %d problem(s) in %d of %d font(s)", total, failed, len(args))
+			}
+			return nil
+		},
+	}
+}
+
+func fontsDupesCmd() *cobra.Command {
+	var threshold float64
+
+	cmd := &cobra.Command{
+		Use:   "dupes",
+		Short: "Report fonts that are identical or nearly identical to another font",
+		Long: `Report pairs of fonts whose glyphs are identical or nearly so. Identical
+fonts render any text the same and are listed as aliases of the first
+name by 'fig fonts list'. Near duplicates share at least --threshold of
+their glyphs.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if threshold <= 0 || threshold > 1 {
+				return fmt.Errorf("--threshold must be above 0 and at most 1")
+			}
+			dupes, err := newEngine(cmd).FontDuplicates(threshold)
+			if err != nil {
+				return err
+			}
+
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			fmt.Fprintln(tw, "FONT\tDUPLICATE\tMATCH")
+			for _, d := range dupes {
+				match := "identical"
+				if !d.Exact {
+					match = fmt.Sprintf("%.0f%% of glyphs", d.Similarity*100)
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\n", d.Canonical, d.Alias, match)
+			}
+			return tw.Flush()
+		},
+	}
+
+	cmd.Flags().Float64Var(&threshold, "threshold", 0.9, "Share of glyphs two fonts must have in common to be reported")
+
+	return cmd
+}
+
+// readFontSource returns the raw data of a font given as a file path or a
+// font name, and the file name to report problems against.
+func readFontSource(cmd *cobra.Command, ref string) ([]byte, string, error) {
+	if font.IsPath(ref) {
+		data, _, err := font.FileLoader{}.Load(ref)
+		return data, strings.TrimPrefix(ref, "file://"), err
+	}
+	nerr := &font.NotFoundError{Name: ref}
+	for _, l := range fontLoaders(cmd) {
+		if _, ok := l.(font.FileLoader); ok {
+			continue
+		}
+		data, format, err := l.Load(ref)
+		if errors.Is(err, font.ErrFontNotFound) {
+			nerr.Loaders = append(nerr.Loaders, font.LoaderName(l))
+			continue
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		return data, ref + "." + format.String(), nil
+	}
+	names, _ := newEngine(cmd).ListFonts()
+	if match, ok := font.ResolveName(ref, names); ok && match != ref {
+		return readFontSource(cmd, match)
+	}
+	nerr.Suggestions = font.Suggest(ref, names, 3)
+	return nil, "", nerr
+}
+
+func fontsSubsetCmd() *cobra.Command {
+	var chars, out string
+
+	cmd := &cobra.Command{
+		Use:   "subset --chars <chars> <file|font>",
+		Short: "Write a copy of a font that only keeps the glyphs for the given characters",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if chars == "" {
+				return fmt.Errorf("--chars is required")
+			}
+			f, err := newEngine(cmd).Font(args[0])
+			if err != nil {
+				return err
+			}
+			return writeFont(cmd, out, f.Subset([]rune(chars)))
+		},
+	}
+
+	cmd.Flags().StringVar(&chars, "chars", "", "Characters to keep; the space is always kept")
+	cmd.Flags().StringVarP(&out, "out", "o", "", "Write the font to this file instead of stdout")
+
+	return cmd
+}
+
+func fontsConvertCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "convert <file|font> <out.flf|out.tlf>",
+		Short: "Convert a font between the figlet (.flf) and TOIlet (.tlf) formats",
+		Long: `Convert a font between the figlet (.flf) and TOIlet (.tlf) formats, chosen
+by the extension of the output file. Converting to .flf replaces characters
+that plain figlet fonts cannot hold, such as block and box-drawing
+characters, with ASCII look-alikes and prints a warning for each glyph that
+changed.`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := args[1]
+			var to font.Format
+			switch strings.ToLower(filepath.Ext(out)) {
+			case ".flf":
+				to = font.FormatFLF
+			case ".tlf":
+				to = font.FormatTLF
+			default:
+				return fmt.Errorf("%s: output must end in .flf or .tlf: %w", out, font.ErrUnsupportedFormat)
+			}
+
+			f, err := newEngine(cmd).Font(args[0])
+			if err != nil {
+				return err
+			}
+			converted, warnings := font.Convert(f, to)
+			for _, w := range warnings {
+				fmt.Fprintf(cmd.ErrOrStderr(), "fig: warning: %s\n", w)
+			}
+			return writeFont(cmd, out, converted)
+		},
+	}
+}
+
+func fontsImportCmd() *cobra.Command {
+	var (
+		from, fill, out string
+		halfBlocks      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import <file.bdf|file.psf>",
+		Short: "Convert an X11 BDF or Linux console PSF bitmap font into a FIGlet font",
+		Long: `Convert an X11 BDF or Linux console PSF bitmap font into a FIGlet font.
+Each pixel becomes the --char fill character, or with --half-blocks each pair
+of pixel rows becomes one row of half-block characters. The font is written
+to --out, as a TOIlet font when it ends in .tlf, or to stdout.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if utf8.RuneCountInString(fill) != 1 {
+				return fmt.Errorf("--char must be a single character, got %q", fill)
+			}
+			path := args[0]
+			if from == "" {
+				from = bitmapFormat(path)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+
+			var bitmap *font.Bitmap
+			switch from {
+			case "bdf":
+				bitmap, err = font.ParseBDF(data)
+			case "psf":
+				bitmap, err = font.ParsePSF(data)
+			default:
+				return fmt.Errorf("%s: unknown bitmap format, pass --from bdf or --from psf: %w", path, font.ErrUnsupportedFormat)
+			}
+			if err != nil {
+				var perr *font.ParseError
+				if errors.As(err, &perr) {
+					perr.File = path
+				}
+				return err
+			}
+
+			fillRune, _ := utf8.DecodeRuneInString(fill)
+			name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+			if out != "" {
+				name = strings.TrimSuffix(filepath.Base(out), filepath.Ext(out))
+			}
+			f, err := font.FromBitmap(bitmap, name, font.BitmapOptions{Fill: fillRune, HalfBlocks: halfBlocks})
+			if err != nil {
+				return err
+			}
+			if strings.EqualFold(filepath.Ext(out), ".tlf") {
+				f, _ = font.Convert(f, font.FormatTLF)
+			}
+			return writeFont(cmd, out, f)
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Bitmap format, bdf or psf (default: from the file extension)")
+	cmd.Flags().StringVar(&fill, "char", "#", "Character drawn for each pixel")
+	cmd.Flags().BoolVar(&halfBlocks, "half-blocks", false, "Draw pairs of pixel rows with half-block characters")
+	cmd.Flags().StringVarP(&out, "out", "o", "", "Write the font to this file instead of stdout")
+
+	return cmd
+}
+
+// bitmapFormat guesses the format of a bitmap font from its file name,
+// including compressed console fonts such as lat1-16.psf.gz.
+func bitmapFormat(path string) string {
+	name := strings.TrimSuffix(strings.ToLower(path), ".gz")
+	switch filepath.Ext(name) {
+	case ".bdf":
+		return "bdf"
+	case ".psf", ".psfu":
+		return "psf"
+	}
+	return ""
+}
+
+// writeFont writes f to the file at path, or to stdout when path is empty.
+func writeFont(cmd *cobra.Command, path string, f *font.Font) error {
+	if path == "" {
+		return font.Write(cmd.OutOrStdout(), f)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := font.Write(file, f); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func fontsInstallCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "install <file>...",
+		Short: "Validate font files and copy them into the user font directory",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := userFontDir()
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return err
+			}
+
+			for _, path := range args {
+				ext := strings.ToLower(filepath.Ext(path))
+				if ext != ".flf" && ext != ".tlf" {
+					return fmt.Errorf("%s: not a font file, expected .flf or .tlf: %w", path, font.ErrUnsupportedFormat)
+				}
+				// Validate and copy the same bytes, read once.
+				data, err := os.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+				f, err := font.Parse(data, name, path)
+				if err != nil {
+					var perr *font.ParseError
+					if errors.As(err, &perr) {
+						perr.File = path
+					}
+					return err
+				}
+
+				dest := filepath.Join(dir, filepath.Base(path))
+				if err := os.WriteFile(dest, data, 0644); err != nil {
+					return err
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "installed %s -> %s\n", f.Name(), dest)
+			}
+			return nil
+		},
+	}
+}
@@ -2,32 +2,53 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"slices"
 	"strings"
 	"syscall"
 
+	"github.com/phantompunk/fig/assets"
 	"github.com/phantompunk/fig/internal/font"
-	"github.com/phantompunk/fig/internal/input"
 	"github.com/phantompunk/fig/internal/render"
-	"github.com/phantompunk/fig/internal/tui"
 	"github.com/phantompunk/fig/internal/vcs"
 	"github.com/spf13/cobra"
 )
 
-var (
-	fontName  string
-	listFonts bool
-	center    bool
-	right     bool
-	// rtl       bool
+// Exit codes, so scripts can tell why fig failed without parsing messages.
+const (
+	exitError             = 1 // any other failure
+	exitFontNotFound      = 3
+	exitInvalidFont       = 4 // the font failed to parse or exceeds a limit
+	exitUnsupportedFormat = 5
 )
 
 func main() {
 	if err := execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "fig: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
+	}
+}
+
+// exitCode returns the exit code for err. A file in the wrong format is
+// reported as such even though the parser wraps it in a ParseError.
+func exitCode(err error) int {
+	var (
+		perr *font.ParseError
+		lerr *font.LimitError
+	)
+	switch {
+	case errors.Is(err, font.ErrUnsupportedFormat):
+		return exitUnsupportedFormat
+	case errors.Is(err, font.ErrFontNotFound):
+		return exitFontNotFound
+	case errors.As(err, &perr), errors.As(err, &lerr):
+		return exitInvalidFont
+	default:
+		return exitError
 	}
 }
 
@@ -38,20 +59,42 @@ func execute() error {
 	return buildCmd().ExecuteContext(ctx)
 }
 
+// buildCmd returns the root command. Bare arguments and piped input render
+// text exactly like `fig render`, so `fig -f slant Hello` and `echo x | fig`
+// keep working alongside the subcommands.
 func buildCmd() *cobra.Command {
+	var (
+		flags renderFlags
+		list  bool
+	)
+
 	cmd := &cobra.Command{
-		Use:          "fig",
+		Use:          "fig [text]",
 		Short:        "Render text as ASCII art using FIGlet fonts",
 		Args:         cobra.ArbitraryArgs,
-		RunE:         run,
 		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if list {
+				return listFontNames(cmd)
+			}
+			return runRender(cmd, args, &flags, true)
+		},
 	}
 
-	cmd.Flags().BoolVarP(&listFonts, "list-fonts", "l", false, "List all available fonts")
-	cmd.Flags().StringVarP(&fontName, "font", "f", "standard", "Specify a font, default is standard")
-	cmd.Flags().BoolVarP(&center, "center", "c", false, "Center text in terminal")
-	cmd.Flags().BoolVarP(&right, "right", "r", false, "Right align text in terminal")
-	// cmd.Flags().BoolVarP(&rtl, "right-to-left", "rtl", false, "Print text right to left")
+	cmd.Flags().BoolVarP(&list, "list-fonts", "l", false, "List all available fonts, see 'fig fonts list' for details")
+	cmd.PersistentFlags().StringArray("font-dir", nil, "Search this directory or .zip bundle for fonts first (repeatable)")
+	flags.register(cmd)
+
+	cmd.AddCommand(
+		renderCmd(),
+		fontsCmd(),
+		tuiCmd(),
+		designCmd(),
+		animateCmd(),
+		clockCmd(),
+		countdownCmd(),
+		showcaseCmd(),
+	)
 
 	v, commit := vcs.Version()
 	cmd.Version = v
@@ -60,46 +103,67 @@ func buildCmd() *cobra.Command {
 	return cmd
 }
 
-func run(cmd *cobra.Command, args []string) error {
-	if listFonts {
-		fonts := font.ListFonts()
-		fmt.Println("Supported fonts:", strings.Join(fonts, ", "))
-		return nil
-	}
-
-	src := input.Resolve(args)
-	msg, err := src.Read()
+// listFontNames prints the legacy -l listing: every available font name on
+// one line. Unlike `fig fonts list` it parses no fonts.
+func listFontNames(cmd *cobra.Command) error {
+	names, err := newEngine(cmd).ListFonts()
 	if err != nil {
 		return err
 	}
+	slices.Sort(names)
+	fmt.Fprintln(cmd.OutOrStdout(), "Supported fonts:", strings.Join(names, ", "))
+	return nil
+}
 
-	if len(msg) == 0 {
-		return tui.Start()
+// newEngine returns a render engine that searches the font directories
+// configured for cmd before the bundled set, and knows the bundled fonts'
+// aliases.
+func newEngine(cmd *cobra.Command) *render.Engine {
+	e := render.New(fontLoaders(cmd)...)
+	if aliases, err := font.LoadAliases(assets.FontsYAML); err == nil {
+		e.SetFontAliases(aliases)
 	}
+	return e
+}
 
-	if center && right {
-		return fmt.Errorf("--center and --right are mutually exclusive")
+// fontLoaders returns the font sources in search order: font files named by
+// path, each --font-dir in the order given, the directories in
+// $FIGLET_FONTDIR, the user font directory and finally the bundled fonts.
+// Entries ending in .zip are read as font bundles; bundles that fail to open
+// are reported and skipped.
+func fontLoaders(cmd *cobra.Command) []font.FontLoader {
+	dirs, _ := cmd.Flags().GetStringArray("font-dir")
+	if env := os.Getenv("FIGLET_FONTDIR"); env != "" {
+		dirs = append(dirs, filepath.SplitList(env)...)
 	}
-	align := render.AlignLeft
-	if center {
-		align = render.AlignCenter
-	} else if right {
-		align = render.AlignRight
+	if dir, err := userFontDir(); err == nil {
+		dirs = append(dirs, dir)
 	}
 
-	engine := render.New(font.BundledLoader())
-	out, err := engine.Render(msg, render.RenderOptions{FontName: fontName, Align: align})
+	loaders := make([]font.FontLoader, 0, len(dirs)+2)
+	loaders = append(loaders, font.FileLoader{})
+	for _, dir := range dirs {
+		switch {
+		case dir == "":
+		case strings.EqualFold(filepath.Ext(dir), ".zip"):
+			z, err := font.OpenZip(dir)
+			if err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "fig: skipping font bundle: %v\n", err)
+				continue
+			}
+			loaders = append(loaders, z)
+		default:
+			loaders = append(loaders, font.DirLoader{Dir: dir})
+		}
+	}
+	return append(loaders, font.BundledLoader())
+}
+
+// userFontDir returns the directory `fig fonts install` copies fonts into.
+func userFontDir() (string, error) {
+	dir, err := os.UserConfigDir()
 	if err != nil {
-		return err
+		return "", err
 	}
-	fmt.Print(out)
-
-	// Legacy render
-	// font, err := font.LoadFont(fontName)
-	// if err != nil {
-	// 	return err
-	// }
-	//
-	// fmt.Println(font.Render(msg))
-	return nil
+	return filepath.Join(dir, "fig", "fonts"), nil
 }
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/phantompunk/fig/internal/export"
+	"github.com/phantompunk/fig/internal/graphics"
+	"github.com/phantompunk/fig/internal/input"
+	"github.com/phantompunk/fig/internal/render"
+	"github.com/phantompunk/fig/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+// renderFlags are shared by `fig render` and the root command.
+type renderFlags struct {
+	font     string
+	controls []string
+	center   bool
+	right    bool
+	format   string
+	protocol string
+	cellSize string
+	fgColor  string
+	bgColor  string
+	sauce    export.Sauce
+	// rtl      bool
+}
+
+func (f *renderFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&f.font, "font", "f", "standard", "Specify a font, default is standard")
+	cmd.Flags().StringArrayVarP(&f.controls, "control", "C", nil, "Apply a FIGlet control file to the input (repeatable)")
+	cmd.Flags().BoolVarP(&f.center, "center", "c", false, "Center text in terminal")
+	cmd.Flags().BoolVarP(&f.right, "right", "r", false, "Right align text in terminal")
+	cmd.Flags().StringVar(&f.format, "format", "text", "Output format: text, json or ans")
+	cmd.Flags().StringVar(&f.protocol, "graphics", "", "Emit an inline image using the sixel or kitty protocol")
+	cmd.Flags().StringVar(&f.cellSize, "cell", "10x20", "Pixel size of one character cell for --graphics")
+	cmd.Flags().StringVar(&f.fgColor, "fg", "#ffffff", "Foreground color for --graphics")
+	cmd.Flags().StringVar(&f.bgColor, "bg", "", "Background color for --graphics, transparent when empty")
+	cmd.Flags().StringVar(&f.sauce.Title, "title", "", "SAUCE title for --format ans, defaults to the text")
+	cmd.Flags().StringVar(&f.sauce.Author, "author", "", "SAUCE author for --format ans")
+	cmd.Flags().StringVar(&f.sauce.Group, "group", "", "SAUCE group for --format ans")
+	cmd.Flags().StringVar(&f.sauce.Font, "sauce-font", "IBM VGA", "SAUCE font name for --format ans")
+	// cmd.Flags().BoolVarP(&f.rtl, "right-to-left", "rtl", false, "Print text right to left")
+}
+
+func renderCmd() *cobra.Command {
+	var flags renderFlags
+
+	cmd := &cobra.Command{
+		Use:   "render [text]",
+		Short: "Render text as ASCII art (the default command)",
+		Args:  cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRender(cmd, args, &flags, false)
+		},
+	}
+	flags.register(cmd)
+
+	return cmd
+}
+
+// runRender renders the text from args or stdin. With no text at all the root
+// command opens the TUI, while `fig render` reports an error.
+func runRender(cmd *cobra.Command, args []string, flags *renderFlags, tuiFallback bool) error {
+	src := input.Resolve(args)
+	msg, err := src.Read()
+	if err != nil {
+		return err
+	}
+
+	if len(msg) == 0 {
+		if tuiFallback {
+			return tui.Start(newEngine(cmd))
+		}
+		return fmt.Errorf("nothing to render: pass text as arguments or on stdin")
+	}
+
+	if flags.center && flags.right {
+		return fmt.Errorf("--center and --right are mutually exclusive")
+	}
+	align := render.AlignLeft
+	if flags.center {
+		align = render.AlignCenter
+	} else if flags.right {
+		align = render.AlignRight
+	}
+
+	engine := newEngine(cmd)
+	opts := render.RenderOptions{FontName: flags.font, Align: align, Controls: flags.controls}
+
+	if flags.protocol != "" {
+		return renderGraphics(cmd, engine, msg, opts, flags)
+	}
+
+	switch flags.format {
+	case "text":
+		out, err := engine.Render(msg, opts)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(cmd.OutOrStdout(), out)
+	case "json":
+		layout, err := engine.Layout(msg, opts)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(cmd.OutOrStdout())
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(layout); err != nil {
+			return err
+		}
+	case "ans":
+		layout, err := engine.Layout(msg, opts)
+		if err != nil {
+			return err
+		}
+		sauce := flags.sauce
+		if sauce.Title == "" {
+			sauce.Title = msg
+		}
+		replaced, err := export.WriteANS(cmd.OutOrStdout(), layout.Lines, sauce)
+		if err != nil {
+			return err
+		}
+		if replaced > 0 {
+			fmt.Fprintf(cmd.ErrOrStderr(), "fig: %d character(s) have no CP437 equivalent and were replaced with '?'\n", replaced)
+		}
+	default:
+		return fmt.Errorf("unknown format %q: expected text, json or ans", flags.format)
+	}
+
+	return nil
+}
+
+func renderGraphics(cmd *cobra.Command, engine *render.Engine, msg string, opts render.RenderOptions, flags *renderFlags) error {
+	if cmd.Flags().Changed("format") {
+		return fmt.Errorf("--graphics and --format are mutually exclusive")
+	}
+
+	gopts := graphics.DefaultOptions()
+	w, h, err := graphics.ParseCellSize(flags.cellSize)
+	if err != nil {
+		return err
+	}
+	gopts.CellWidth, gopts.CellHeight = w, h
+
+	fg, err := graphics.ParseColor(flags.fgColor)
+	if err != nil {
+		return err
+	}
+	gopts.Foreground = fg
+	if flags.bgColor != "" {
+		bg, err := graphics.ParseColor(flags.bgColor)
+		if err != nil {
+			return err
+		}
+		gopts.Background = bg
+	}
+
+	layout, err := engine.Layout(msg, opts)
+	if err != nil {
+		return err
+	}
+	img := graphics.Rasterize(layout.Lines, gopts)
+
+	out := cmd.OutOrStdout()
+	switch flags.protocol {
+	case "sixel":
+		err = graphics.EncodeSixel(out, img)
+	case "kitty":
+		err = graphics.EncodeKitty(out, img)
+	default:
+		return fmt.Errorf("unknown graphics protocol %q: expected sixel or kitty", flags.protocol)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out)
+	return nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/phantompunk/fig/assets"
+	"github.com/phantompunk/fig/internal/export"
+	"github.com/phantompunk/fig/internal/font"
+	"github.com/phantompunk/fig/internal/input"
+	"github.com/phantompunk/fig/internal/render"
+	"github.com/spf13/cobra"
+)
+
+type showcaseFlags struct {
+	tag  string
+	html string
+}
+
+func showcaseCmd() *cobra.Command {
+	var flags showcaseFlags
+
+	cmd := &cobra.Command{
+		Use:   "showcase [text]",
+		Short: "Render text in every font, or every font with a tag",
+		Args:  cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runShowcase(cmd, args, flags)
+		},
+	}
+
+	cmd.Flags().StringVarP(&flags.tag, "tag", "t", "", "Only use fonts with this tag")
+	cmd.Flags().StringVar(&flags.html, "html", "", "Write a static HTML gallery to this file instead of the terminal")
+
+	return cmd
+}
+
+func runShowcase(cmd *cobra.Command, args []string, flags showcaseFlags) error {
+	msg, err := input.Resolve(args).Read()
+	if err != nil {
+		return err
+	}
+	if len(msg) == 0 {
+		return fmt.Errorf("nothing to render: pass text as arguments or on stdin")
+	}
+
+	engine := newEngine(cmd)
+	names, err := engine.ListFonts()
+	if err != nil {
+		return err
+	}
+	slices.Sort(names)
+
+	if flags.tag != "" {
+		tags, err := font.LoadTagMap(assets.FontsYAML)
+		if err != nil {
+			return err
+		}
+		names = slices.DeleteFunc(names, func(name string) bool { return !tags.HasTag(name, flags.tag) })
+		if len(names) == 0 {
+			return fmt.Errorf("no fonts tagged %q", flags.tag)
+		}
+	}
+
+	specimens := make([]export.Specimen, 0, len(names))
+	for _, r := range renderAll(engine, msg, names) {
+		if r.err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "fig: skipping %s: %v\n", r.font, r.err)
+			continue
+		}
+		specimens = append(specimens, export.Specimen{Font: r.font, Text: r.out})
+	}
+
+	if flags.html != "" {
+		out, err := os.Create(flags.html)
+		if err != nil {
+			return err
+		}
+		if err := export.WriteGallery(out, msg, specimens); err != nil {
+			out.Close()
+			return err
+		}
+		return out.Close()
+	}
+
+	w := cmd.OutOrStdout()
+	for i, s := range specimens {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "== %s %s\n", s.Font, strings.Repeat("=", max(40-len(s.Font), 3)))
+		fmt.Fprint(w, s.Text)
+	}
+	return nil
+}
+
+type rendered struct {
+	font string
+	out  string
+	err  error
+}
+
+// renderAll renders text in each font concurrently on the shared engine and
+// returns the results in the order of fonts.
+func renderAll(engine *render.Engine, text string, fonts []string) []rendered {
+	results := make([]rendered, len(fonts))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for range min(runtime.GOMAXPROCS(0), len(fonts)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				out, err := engine.Render(text, render.RenderOptions{FontName: fonts[i]})
+				results[i] = rendered{font: fonts[i], out: out, err: err}
+			}
+		}()
+	}
+	for i := range fonts {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
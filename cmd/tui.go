@@ -0,0 +1,17 @@
+package main
+
+import (
+	"github.com/phantompunk/fig/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+func tuiCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "tui",
+		Short: "Browse and preview fonts interactively",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return tui.Start(newEngine(cmd))
+		},
+	}
+}
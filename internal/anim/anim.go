@@ -0,0 +1,227 @@
+package anim
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/phantompunk/fig/internal/render"
+)
+
+// Frame is one full screen of an animation, shown At after the start.
+type Frame struct {
+	At    time.Duration
+	Lines []string
+}
+
+// Draw returns the terminal output for the frame: cursor home, clear screen,
+// then the frame's lines separated by CRLF so it renders correctly in raw
+// terminals and asciicast players alike.
+func (f Frame) Draw() string {
+	return "\x1b[H\x1b[2J" + strings.Join(f.Lines, "\r\n")
+}
+
+// Options describes the screen an effect plays on and how fast it plays.
+type Options struct {
+	Width    int           // screen columns
+	Height   int           // screen rows
+	FPS      int           // frames per second; defaults to DefaultFPS
+	Duration time.Duration // total length; 0 uses the effect's natural length
+}
+
+// DefaultFPS is used when Options.FPS is not set.
+const DefaultFPS = 12
+
+// defaultLoop is the length of effects that have no natural end.
+const defaultLoop = 5 * time.Second
+
+func (o Options) interval() time.Duration {
+	fps := o.FPS
+	if fps <= 0 {
+		fps = DefaultFPS
+	}
+	return time.Second / time.Duration(fps)
+}
+
+// frameCount returns how many frames fit in the configured duration, or
+// natural when no duration is set.
+func (o Options) frameCount(natural int) int {
+	if o.Duration <= 0 {
+		return natural
+	}
+	return max(int(o.Duration/o.interval()), 1)
+}
+
+// Effect turns a rendered banner into a timed sequence of frames.
+type Effect func(l *render.Layout, opts Options) []Frame
+
+var effects = map[string]Effect{
+	"typewriter": Typewriter,
+	"marquee":    Marquee,
+	"bounce":     Bounce,
+	"blink":      Blink,
+}
+
+// Lookup returns the named effect.
+func Lookup(name string) (Effect, error) {
+	e, ok := effects[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown effect %q: expected one of %s", name, strings.Join(Names(), ", "))
+	}
+	return e, nil
+}
+
+// Names returns the available effect names in alphabetical order.
+func Names() []string {
+	names := make([]string, 0, len(effects))
+	for name := range effects {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Typewriter reveals the banner one source character at a time, using the
+// layout's column spans so smushed neighbours appear together. With a
+// duration set the reveal is spread across it.
+func Typewriter(l *render.Layout, opts Options) []Frame {
+	step := opts.interval()
+	if opts.Duration > 0 && len(l.Spans) > 0 {
+		step = opts.Duration / time.Duration(len(l.Spans)+1)
+	}
+
+	frames := make([]Frame, 0, len(l.Spans)+1)
+	for i := 0; i <= len(l.Spans); i++ {
+		cut := 0
+		if i > 0 {
+			cut = l.Spans[i-1].End
+		}
+		visible := make([]string, len(l.Lines))
+		for y, line := range l.Lines {
+			visible[y] = clip(line, 0, cut)
+		}
+		frames = append(frames, Frame{
+			At:    time.Duration(i) * step,
+			Lines: place(visible, 0, 0, opts.Width, opts.Height),
+		})
+	}
+	return frames
+}
+
+// Marquee scrolls the banner from the right edge of the screen until it has
+// left on the left, one column per frame, wrapping when a longer duration
+// is requested.
+func Marquee(l *render.Layout, opts Options) []Frame {
+	pass := opts.Width + l.Width
+	n := opts.frameCount(pass + 1)
+	y := max((opts.Height-len(l.Lines))/2, 0)
+
+	frames := make([]Frame, n)
+	for i := range n {
+		x := opts.Width - i%(pass+1)
+		frames[i] = Frame{
+			At:    time.Duration(i) * opts.interval(),
+			Lines: place(l.Lines, x, y, opts.Width, opts.Height),
+		}
+	}
+	return frames
+}
+
+// Bounce moves the banner diagonally around the screen, reversing direction
+// at each edge. Without a duration it plays for five seconds.
+func Bounce(l *render.Layout, opts Options) []Frame {
+	if opts.Duration <= 0 {
+		opts.Duration = defaultLoop
+	}
+	n := opts.frameCount(0)
+
+	maxX := max(opts.Width-l.Width, 0)
+	maxY := max(opts.Height-len(l.Lines), 0)
+	x, y, dx, dy := 0, 0, 1, 1
+
+	frames := make([]Frame, n)
+	for i := range n {
+		frames[i] = Frame{
+			At:    time.Duration(i) * opts.interval(),
+			Lines: place(l.Lines, x, y, opts.Width, opts.Height),
+		}
+		if x+dx < 0 || x+dx > maxX {
+			dx = -dx
+		}
+		if y+dy < 0 || y+dy > maxY {
+			dy = -dy
+		}
+		if maxX > 0 {
+			x += dx
+		}
+		if maxY > 0 {
+			y += dy
+		}
+	}
+	return frames
+}
+
+// blinkPalette is the 256-color cycle used by Blink.
+var blinkPalette = []int{196, 208, 226, 46, 51, 21, 201}
+
+// Blink cycles the banner through a palette of colors, one color per frame.
+// Without a duration it plays for five seconds.
+func Blink(l *render.Layout, opts Options) []Frame {
+	if opts.Duration <= 0 {
+		opts.Duration = defaultLoop
+	}
+	n := opts.frameCount(0)
+	base := place(l.Lines, 0, 0, opts.Width, opts.Height)
+
+	frames := make([]Frame, n)
+	for i := range n {
+		color := blinkPalette[i%len(blinkPalette)]
+		lines := make([]string, len(base))
+		for y, line := range base {
+			if line != "" {
+				line = fmt.Sprintf("\x1b[38;5;%dm%s\x1b[0m", color, line)
+			}
+			lines[y] = line
+		}
+		frames[i] = Frame{At: time.Duration(i) * opts.interval(), Lines: lines}
+	}
+	return frames
+}
+
+// place draws lines onto a blank width x height screen with their top-left
+// corner at (x, y), clipping anything that falls outside. A zero width or
+// height leaves that dimension unclipped. Trailing spaces are trimmed.
+func place(lines []string, x, y, width, height int) []string {
+	if height <= 0 {
+		height = y + len(lines)
+	}
+	screen := make([]string, height)
+	for i, line := range lines {
+		row := y + i
+		if row < 0 || row >= height {
+			continue
+		}
+		runes := []rune(line)
+		end := len(runes)
+		if width > 0 {
+			end = min(end, width-x)
+		}
+		start := max(-x, 0)
+		if start >= end {
+			continue
+		}
+		screen[row] = strings.TrimRight(strings.Repeat(" ", max(x, 0))+string(runes[start:end]), " ")
+	}
+	return screen
+}
+
+// clip returns the columns [from, to) of line.
+func clip(line string, from, to int) string {
+	runes := []rune(line)
+	to = min(to, len(runes))
+	if from >= to {
+		return ""
+	}
+	return string(runes[from:to])
+}
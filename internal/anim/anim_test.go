@@ -0,0 +1,105 @@
+package anim
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phantompunk/fig/internal/render"
+)
+
+// layout returns a one-row banner where each character covers one column.
+func layout(text string) *render.Layout {
+	spans := make([]render.Span, 0, len(text))
+	for i, r := range text {
+		spans = append(spans, render.Span{Char: string(r), Index: i, Start: i, End: i + 1})
+	}
+	return &render.Layout{Height: 1, Width: len(text), Lines: []string{text}, Spans: spans}
+}
+
+func TestTypewriter_revealsOneCharacterPerFrame(t *testing.T) {
+	frames := Typewriter(layout("abc"), Options{Width: 10, Height: 1, FPS: 10})
+
+	want := []string{"", "a", "ab", "abc"}
+	if len(frames) != len(want) {
+		t.Fatalf("want %d frames, got %d", len(want), len(frames))
+	}
+	for i, f := range frames {
+		if f.Lines[0] != want[i] {
+			t.Errorf("frame %d: got %q, want %q", i, f.Lines[0], want[i])
+		}
+		if f.At != time.Duration(i)*100*time.Millisecond {
+			t.Errorf("frame %d: at %v", i, f.At)
+		}
+	}
+}
+
+func TestTypewriter_durationSpreadsReveal(t *testing.T) {
+	frames := Typewriter(layout("abc"), Options{Duration: 2 * time.Second})
+
+	if last := frames[len(frames)-1].At; last != 1500*time.Millisecond {
+		t.Errorf("last frame at %v, want 1.5s", last)
+	}
+}
+
+func TestMarquee_scrollsAcrossScreen(t *testing.T) {
+	frames := Marquee(layout("ab"), Options{Width: 4, Height: 1, FPS: 10})
+
+	// Enters from the right edge and leaves on the left: 4+2+1 frames.
+	want := []string{"", "   a", "  ab", " ab", "ab", "b", ""}
+	if len(frames) != len(want) {
+		t.Fatalf("want %d frames, got %d", len(want), len(frames))
+	}
+	for i, f := range frames {
+		if f.Lines[0] != want[i] {
+			t.Errorf("frame %d: got %q, want %q", i, f.Lines[0], want[i])
+		}
+	}
+}
+
+func TestBounce_staysOnScreen(t *testing.T) {
+	frames := Bounce(layout("ab"), Options{Width: 5, Height: 3, FPS: 10, Duration: 2 * time.Second})
+
+	if len(frames) != 20 {
+		t.Fatalf("want 20 frames, got %d", len(frames))
+	}
+	for i, f := range frames {
+		if len(f.Lines) != 3 {
+			t.Fatalf("frame %d: want 3 rows, got %d", i, len(f.Lines))
+		}
+		found := false
+		for _, row := range f.Lines {
+			if len(row) > 5 {
+				t.Errorf("frame %d: row %q wider than screen", i, row)
+			}
+			if strings.Contains(row, "ab") {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("frame %d: banner left the screen: %q", i, f.Lines)
+		}
+	}
+	// The banner moves diagonally: second frame is one row and column in.
+	if frames[1].Lines[1] != " ab" {
+		t.Errorf("unexpected second frame %q", frames[1].Lines)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for _, name := range Names() {
+		if _, err := Lookup(name); err != nil {
+			t.Errorf("Lookup(%q): %v", name, err)
+		}
+	}
+	if _, err := Lookup("spin"); err == nil {
+		t.Error("expected error for unknown effect")
+	}
+}
+
+func TestFrameDraw(t *testing.T) {
+	got := Frame{Lines: []string{"a", "b"}}.Draw()
+	if got != "\x1b[H\x1b[2Ja\r\nb" {
+		t.Errorf("unexpected draw output %q", got)
+	}
+}
@@ -0,0 +1,63 @@
+package anim
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+const (
+	hideCursor = "\x1b[?25l"
+	showCursor = "\x1b[?25h"
+)
+
+// Play writes frames to w at their scheduled times, repeating them when loop
+// is set. It hides the cursor while playing and always restores it, leaving
+// the last frame on screen. Cancelling ctx stops playback early without
+// error, so an interrupted animation exits cleanly.
+func Play(ctx context.Context, w io.Writer, frames []Frame, loop bool) (err error) {
+	if len(frames) == 0 {
+		return nil
+	}
+	if _, err := io.WriteString(w, hideCursor); err != nil {
+		return err
+	}
+	defer func() {
+		if _, werr := io.WriteString(w, showCursor+"\r\n"); err == nil {
+			err = werr
+		}
+	}()
+
+	// A looped animation holds its last frame for one frame interval before
+	// starting over, so the cycle keeps an even rhythm.
+	period := frames[len(frames)-1].At
+	if len(frames) > 1 {
+		period += frames[len(frames)-1].At - frames[len(frames)-2].At
+	}
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	<-timer.C
+
+	start := time.Now()
+	for offset := time.Duration(0); ; offset += period {
+		for _, f := range frames {
+			if wait := time.Until(start.Add(offset + f.At)); wait > 0 {
+				timer.Reset(wait)
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-timer.C:
+				}
+			} else if ctx.Err() != nil {
+				return nil
+			}
+			if _, err := io.WriteString(w, f.Draw()); err != nil {
+				return err
+			}
+		}
+		if !loop || period <= 0 {
+			return nil
+		}
+	}
+}
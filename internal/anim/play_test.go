@@ -0,0 +1,75 @@
+package anim
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPlay_writesEveryFrame(t *testing.T) {
+	frames := []Frame{
+		{At: 0, Lines: []string{"one"}},
+		{At: time.Millisecond, Lines: []string{"two"}},
+	}
+
+	var buf bytes.Buffer
+	if err := Play(context.Background(), &buf, frames, false); err != nil {
+		t.Fatalf("play: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.HasPrefix(out, hideCursor) {
+		t.Errorf("cursor not hidden: %q", out)
+	}
+	if !strings.HasSuffix(out, showCursor+"\r\n") {
+		t.Errorf("cursor not restored: %q", out)
+	}
+	if strings.Index(out, "one") > strings.Index(out, "two") {
+		t.Errorf("frames out of order: %q", out)
+	}
+}
+
+func TestPlay_cancelStopsLoop(t *testing.T) {
+	frames := []Frame{
+		{At: 0, Lines: []string{"a"}},
+		{At: time.Millisecond, Lines: []string{"b"}},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	var buf bytes.Buffer
+	done := make(chan error, 1)
+	go func() { done <- Play(ctx, &buf, frames, true) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("cancelled playback should not error, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("looping playback ignored context cancellation")
+	}
+	if !strings.HasSuffix(buf.String(), showCursor+"\r\n") {
+		t.Errorf("cursor not restored after cancel")
+	}
+}
+
+func TestBlink_cyclesColors(t *testing.T) {
+	frames := Blink(layout("ab"), Options{Width: 10, Height: 1, FPS: 10, Duration: time.Second})
+
+	if len(frames) != 10 {
+		t.Fatalf("want 10 frames, got %d", len(frames))
+	}
+	if frames[0].Lines[0] == frames[1].Lines[0] {
+		t.Error("consecutive frames should use different colors")
+	}
+	if frames[0].Lines[0] != frames[len(blinkPalette)].Lines[0] {
+		t.Error("palette should repeat")
+	}
+	if !strings.Contains(frames[0].Lines[0], "ab") {
+		t.Errorf("banner missing from frame: %q", frames[0].Lines[0])
+	}
+}
@@ -0,0 +1,87 @@
+package anim
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/phantompunk/fig/internal/render"
+)
+
+// Ticker keeps a banner centered on screen while its text changes over time,
+// as in a clock or countdown. Each tick it asks Text for the current string;
+// Render is only called when that string changes, and the screen is only
+// redrawn when the text or the screen size does.
+type Ticker struct {
+	// Render lays out text, typically through render.Engine.Layout.
+	Render func(text string) (*render.Layout, error)
+	// Text returns the text to show at now and whether it is the final text.
+	Text func(now time.Time) (text string, done bool)
+	// Size returns the current screen size in columns and rows.
+	Size func() (cols, rows int)
+	// Interval is how often Text and Size are polled.
+	Interval time.Duration
+	// Now returns the current time; defaults to time.Now.
+	Now func() time.Time
+}
+
+// Run draws to w until Text reports done or ctx is cancelled. Like Play it
+// hides the cursor while running and cancellation is not an error.
+func (t *Ticker) Run(ctx context.Context, w io.Writer) (err error) {
+	now := t.Now
+	if now == nil {
+		now = time.Now
+	}
+
+	if _, err := io.WriteString(w, hideCursor); err != nil {
+		return err
+	}
+	defer func() {
+		if _, werr := io.WriteString(w, showCursor+"\r\n"); err == nil {
+			err = werr
+		}
+	}()
+
+	ticker := time.NewTicker(t.Interval)
+	defer ticker.Stop()
+
+	var (
+		lastText   string
+		layout     *render.Layout
+		cols, rows int
+	)
+	for {
+		text, done := t.Text(now())
+		c, r := t.Size()
+
+		changed := layout == nil || text != lastText
+		if changed {
+			if layout, err = t.Render(text); err != nil {
+				return err
+			}
+			lastText = text
+		}
+		if changed || c != cols || r != rows {
+			cols, rows = c, r
+			if _, err := io.WriteString(w, Center(layout, cols, rows).Draw()); err != nil {
+				return err
+			}
+		}
+		if done {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Center returns a frame with the banner centered on a cols x rows screen.
+func Center(l *render.Layout, cols, rows int) Frame {
+	x := max((cols-l.Width)/2, 0)
+	y := max((rows-len(l.Lines))/2, 0)
+	return Frame{Lines: place(l.Lines, x, y, cols, rows)}
+}
@@ -0,0 +1,99 @@
+package anim
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phantompunk/fig/internal/render"
+)
+
+func TestTicker_rendersOnlyOnChange(t *testing.T) {
+	texts := []string{"1", "1", "1", "2", "2", "3"}
+	tick := 0
+	renders := 0
+
+	tk := &Ticker{
+		Render: func(text string) (*render.Layout, error) {
+			renders++
+			return layout(text), nil
+		},
+		Text: func(time.Time) (string, bool) {
+			text := texts[tick]
+			tick++
+			return text, tick == len(texts)
+		},
+		Size:     func() (int, int) { return 10, 3 },
+		Interval: time.Millisecond,
+	}
+
+	var buf bytes.Buffer
+	if err := tk.Run(context.Background(), &buf); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if renders != 3 {
+		t.Errorf("want 3 renders for 3 distinct texts, got %d", renders)
+	}
+	if draws := strings.Count(buf.String(), "\x1b[2J"); draws != 3 {
+		t.Errorf("want 3 redraws, got %d", draws)
+	}
+}
+
+func TestTicker_redrawsOnResize(t *testing.T) {
+	sizes := [][2]int{{10, 3}, {10, 3}, {20, 5}}
+	tick := 0
+	renders := 0
+
+	tk := &Ticker{
+		Render: func(text string) (*render.Layout, error) {
+			renders++
+			return layout(text), nil
+		},
+		Text: func(time.Time) (string, bool) { return "ab", tick == len(sizes)-1 },
+		Size: func() (int, int) {
+			s := sizes[tick]
+			tick++
+			return s[0], s[1]
+		},
+		Interval: time.Millisecond,
+	}
+
+	var buf bytes.Buffer
+	if err := tk.Run(context.Background(), &buf); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if renders != 1 {
+		t.Errorf("resize should not re-render, got %d renders", renders)
+	}
+	if draws := strings.Count(buf.String(), "\x1b[2J"); draws != 2 {
+		t.Errorf("want 2 redraws, got %d", draws)
+	}
+}
+
+func TestTicker_cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tk := &Ticker{
+		Render:   func(text string) (*render.Layout, error) { return layout(text), nil },
+		Text:     func(time.Time) (string, bool) { return "x", false },
+		Size:     func() (int, int) { return 10, 3 },
+		Interval: time.Hour,
+	}
+	if err := tk.Run(ctx, &bytes.Buffer{}); err != nil {
+		t.Errorf("cancelled ticker should not error, got %v", err)
+	}
+}
+
+func TestCenter(t *testing.T) {
+	f := Center(layout("ab"), 6, 3)
+
+	want := []string{"", "  ab", ""}
+	for i, line := range f.Lines {
+		if line != want[i] {
+			t.Errorf("row %d: got %q, want %q", i, line, want[i])
+		}
+	}
+}
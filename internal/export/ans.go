@@ -0,0 +1,101 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"time"
+	"unicode/utf8"
+)
+
+// Sauce holds the SAUCE metadata appended to an .ans file. Text fields longer
+// than the record allows are truncated; Font names the SAUCE font (TInfoS),
+// such as "IBM VGA", that viewers should display the art with.
+type Sauce struct {
+	Title  string
+	Author string
+	Group  string
+	Font   string
+	Date   time.Time
+}
+
+const (
+	sauceRecordSize    = 128
+	sauceDataCharacter = 1 // DataType: character-based
+	sauceFileANSi      = 1 // FileType: ANSi
+)
+
+// WriteANS writes lines as CP437 ANSI art followed by an EOF marker and a
+// SAUCE record. Characters without a CP437 equivalent are written as '?'.
+// It returns the number of characters that had to be replaced.
+func WriteANS(w io.Writer, lines []string, sauce Sauce) (int, error) {
+	var body bytes.Buffer
+	replaced := 0
+	width := 0
+
+	// Reset attributes so the art does not inherit the viewer's colors.
+	body.WriteString("\x1b[0m")
+	for _, line := range lines {
+		width = max(width, utf8.RuneCountInString(line))
+		for _, r := range line {
+			b, ok := toCP437(r)
+			if !ok {
+				b = '?'
+				replaced++
+			}
+			body.WriteByte(b)
+		}
+		body.WriteString("\r\n")
+	}
+
+	fileSize := body.Len()
+	body.WriteByte(0x1a) // SUB: ends the art for DOS-era viewers
+	body.Write(sauceRecord(sauce, fileSize, width, len(lines)))
+
+	_, err := w.Write(body.Bytes())
+	return replaced, err
+}
+
+// sauceRecord builds the 128-byte SAUCE 00 record.
+func sauceRecord(s Sauce, fileSize, width, lines int) []byte {
+	date := s.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	var rec bytes.Buffer
+	rec.WriteString("SAUCE00")
+	rec.Write(sauceField(s.Title, 35, ' '))
+	rec.Write(sauceField(s.Author, 20, ' '))
+	rec.Write(sauceField(s.Group, 20, ' '))
+	rec.WriteString(date.Format("20060102"))
+	binary.Write(&rec, binary.LittleEndian, uint32(fileSize))
+	rec.WriteByte(sauceDataCharacter)
+	rec.WriteByte(sauceFileANSi)
+	binary.Write(&rec, binary.LittleEndian, uint16(min(width, 0xffff))) // TInfo1: columns
+	binary.Write(&rec, binary.LittleEndian, uint16(min(lines, 0xffff))) // TInfo2: lines
+	binary.Write(&rec, binary.LittleEndian, uint16(0))                  // TInfo3
+	binary.Write(&rec, binary.LittleEndian, uint16(0))                  // TInfo4
+	rec.WriteByte(0)                                                    // no comment block
+	rec.WriteByte(0)                                                    // TFlags
+	rec.Write(sauceField(s.Font, 22, 0))
+	return rec.Bytes()
+}
+
+// sauceField encodes s as CP437 into a fixed-size field padded with pad.
+func sauceField(s string, size int, pad byte) []byte {
+	field := bytes.Repeat([]byte{pad}, size)
+	i := 0
+	for _, r := range s {
+		if i == size {
+			break
+		}
+		b, ok := toCP437(r)
+		if !ok {
+			b = '?'
+		}
+		field[i] = b
+		i++
+	}
+	return field
+}
@@ -0,0 +1,61 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/phantompunk/fig/internal/assert"
+)
+
+func TestWriteANS_sauceRecord(t *testing.T) {
+	var buf bytes.Buffer
+	sauce := Sauce{
+		Title:  "Hello",
+		Author: "phantompunk",
+		Group:  "fig",
+		Font:   "IBM VGA",
+		Date:   time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC),
+	}
+	_, err := WriteANS(&buf, []string{"#_#", "| |"}, sauce)
+	assert.NilError(t, err)
+
+	data := buf.Bytes()
+	if len(data) < sauceRecordSize+1 {
+		t.Fatalf("output too short: %d bytes", len(data))
+	}
+	rec := data[len(data)-sauceRecordSize:]
+	assert.Equal(t, data[len(data)-sauceRecordSize-1], byte(0x1a))
+
+	assert.Equal(t, string(rec[0:7]), "SAUCE00")
+	assert.Equal(t, string(rec[7:42]), "Hello"+string(bytes.Repeat([]byte{' '}, 30)))
+	assert.Equal(t, string(bytes.TrimRight(rec[42:62], " ")), "phantompunk")
+	assert.Equal(t, string(bytes.TrimRight(rec[62:82], " ")), "fig")
+	assert.Equal(t, string(rec[82:90]), "20240309")
+	assert.Equal(t, binary.LittleEndian.Uint32(rec[90:94]), uint32(len(data)-sauceRecordSize-1))
+	assert.Equal(t, rec[94], byte(sauceDataCharacter))
+	assert.Equal(t, rec[95], byte(sauceFileANSi))
+	assert.Equal(t, binary.LittleEndian.Uint16(rec[96:98]), uint16(3))  // width
+	assert.Equal(t, binary.LittleEndian.Uint16(rec[98:100]), uint16(2)) // lines
+	assert.Equal(t, string(bytes.TrimRight(rec[106:128], "\x00")), "IBM VGA")
+}
+
+func TestWriteANS_cp437Mapping(t *testing.T) {
+	var buf bytes.Buffer
+	replaced, err := WriteANS(&buf, []string{"█▀▄░╔═╗╭─╮☃"}, Sauce{})
+	assert.NilError(t, err)
+	assert.Equal(t, replaced, 1)
+
+	body := bytes.TrimPrefix(buf.Bytes(), []byte("\x1b[0m"))
+	want := []byte{0xdb, 0xdf, 0xdc, 0xb0, 0xc9, 0xcd, 0xbb, 0xda, 0xc4, 0xbf, '?', '\r', '\n'}
+	if !bytes.HasPrefix(body, want) {
+		t.Errorf("got % x\nwant % x", body[:len(want)], want)
+	}
+}
+
+func TestSauceField_truncates(t *testing.T) {
+	field := sauceField("a title that is much longer than thirty-five characters", 35, ' ')
+	assert.Equal(t, len(field), 35)
+	assert.Equal(t, string(field), "a title that is much longer than th")
+}
@@ -0,0 +1,54 @@
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/phantompunk/fig/internal/anim"
+)
+
+// CastHeader is the first line of an asciicast v2 file.
+type CastHeader struct {
+	Width     int
+	Height    int
+	Title     string
+	Timestamp time.Time
+}
+
+type castHeader struct {
+	Version   int    `json:"version"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Timestamp int64  `json:"timestamp,omitempty"`
+	Title     string `json:"title,omitempty"`
+}
+
+// WriteCast writes frames as an asciicast v2 recording: a JSON header line
+// followed by one output event per frame. The cursor is hidden for the
+// duration of the recording and restored by a final event.
+func WriteCast(w io.Writer, hdr CastHeader, frames []anim.Frame) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	h := castHeader{Version: 2, Width: hdr.Width, Height: hdr.Height, Title: hdr.Title}
+	if !hdr.Timestamp.IsZero() {
+		h.Timestamp = hdr.Timestamp.Unix()
+	}
+	if err := enc.Encode(h); err != nil {
+		return err
+	}
+
+	var last time.Duration
+	for i, f := range frames {
+		data := f.Draw()
+		if i == 0 {
+			data = "\x1b[?25l" + data
+		}
+		if err := enc.Encode([]any{f.At.Seconds(), "o", data}); err != nil {
+			return err
+		}
+		last = f.At
+	}
+	return enc.Encode([]any{last.Seconds(), "o", "\x1b[?25h\r\n"})
+}
@@ -0,0 +1,44 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/phantompunk/fig/internal/anim"
+	"github.com/phantompunk/fig/internal/assert"
+)
+
+func TestWriteCast(t *testing.T) {
+	frames := []anim.Frame{
+		{At: 0, Lines: []string{"a"}},
+		{At: 250 * time.Millisecond, Lines: []string{"ab"}},
+	}
+	hdr := CastHeader{Width: 20, Height: 4, Title: "fig", Timestamp: time.Unix(1700000000, 0)}
+
+	var buf bytes.Buffer
+	assert.NilError(t, WriteCast(&buf, hdr, frames))
+
+	sc := bufio.NewScanner(&buf)
+	var lines [][]byte
+	for sc.Scan() {
+		lines = append(lines, append([]byte(nil), sc.Bytes()...))
+	}
+	assert.Equal(t, len(lines), 4) // header, two frames, cursor restore
+
+	var header map[string]any
+	assert.NilError(t, json.Unmarshal(lines[0], &header))
+	assert.Equal(t, header["version"], any(float64(2)))
+	assert.Equal(t, header["width"], any(float64(20)))
+	assert.Equal(t, header["height"], any(float64(4)))
+	assert.Equal(t, header["timestamp"], any(float64(1700000000)))
+	assert.Equal(t, header["title"], any("fig"))
+
+	var event []any
+	assert.NilError(t, json.Unmarshal(lines[2], &event))
+	assert.Equal(t, event[0], any(0.25))
+	assert.Equal(t, event[1], any("o"))
+	assert.Equal(t, event[2], any(frames[1].Draw()))
+}
@@ -0,0 +1,49 @@
+package export
+
+// cp437High maps bytes 0x80–0xFF of IBM code page 437 to Unicode.
+var cp437High = [128]rune{
+	'Ç', 'ü', 'é', 'â', 'ä', 'à', 'å', 'ç', 'ê', 'ë', 'è', 'ï', 'î', 'ì', 'Ä', 'Å',
+	'É', 'æ', 'Æ', 'ô', 'ö', 'ò', 'û', 'ù', 'ÿ', 'Ö', 'Ü', '¢', '£', '¥', '₧', 'ƒ',
+	'á', 'í', 'ó', 'ú', 'ñ', 'Ñ', 'ª', 'º', '¿', '⌐', '¬', '½', '¼', '¡', '«', '»',
+	'░', '▒', '▓', '│', '┤', '╡', '╢', '╖', '╕', '╣', '║', '╗', '╝', '╜', '╛', '┐',
+	'└', '┴', '┬', '├', '─', '┼', '╞', '╟', '╚', '╔', '╩', '╦', '╠', '═', '╬', '╧',
+	'╨', '╤', '╥', '╙', '╘', '╒', '╓', '╫', '╪', '┘', '┌', '█', '▄', '▌', '▐', '▀',
+	'α', 'ß', 'Γ', 'π', 'Σ', 'σ', 'µ', 'τ', 'Φ', 'Θ', 'Ω', 'δ', '∞', 'φ', 'ε', '∩',
+	'≡', '±', '≥', '≤', '⌠', '⌡', '÷', '≈', '°', '∙', '·', '√', 'ⁿ', '²', '■', ' ',
+}
+
+// cp437Fallback maps common Unicode cells that have no CP437 code point to
+// the closest character that does.
+var cp437Fallback = map[rune]rune{
+	'━': '═', '┃': '║',
+	'╭': '┌', '╮': '┐', '╯': '┘', '╰': '└',
+	'┏': '╔', '┓': '╗', '┗': '╚', '┛': '╝',
+	'┣': '╠', '┫': '╣', '┳': '╦', '┻': '╩', '╋': '╬',
+	'▘': '▀', '▝': '▀', '▖': '▄', '▗': '▄',
+	'▛': '█', '▜': '█', '▙': '█', '▟': '█', '▚': '▒', '▞': '▒',
+	'╱': '/', '╲': '\\', '╳': 'X',
+	'‾': '-', '’': '\'', '‘': '\'', '“': '"', '”': '"',
+}
+
+var unicodeToCP437 = func() map[rune]byte {
+	m := make(map[rune]byte, len(cp437High))
+	for i, r := range cp437High {
+		m[r] = byte(0x80 + i)
+	}
+	return m
+}()
+
+// toCP437 returns the CP437 byte for r. ok is false when r has no mapping,
+// either direct or through the fallback table.
+func toCP437(r rune) (b byte, ok bool) {
+	if r >= 0x20 && r < 0x7f {
+		return byte(r), true
+	}
+	if b, ok := unicodeToCP437[r]; ok {
+		return b, true
+	}
+	if f, ok := cp437Fallback[r]; ok {
+		return toCP437(f)
+	}
+	return 0, false
+}
@@ -0,0 +1,91 @@
+package export
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// Specimen is one font's rendering in a gallery.
+type Specimen struct {
+	Font string
+	Text string // rendered output
+}
+
+// Anchor returns the fragment identifier linking to the specimen, derived
+// from the font name: lower case, with runs of other characters collapsed
+// to single hyphens.
+func (s Specimen) Anchor() string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(s.Font) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+			hyphen = false
+		} else if !hyphen && b.Len() > 0 {
+			b.WriteByte('-')
+			hyphen = true
+		}
+	}
+	return "font-" + strings.TrimSuffix(b.String(), "-")
+}
+
+var galleryTemplate = template.Must(template.New("gallery").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2rem; background: #111; color: #eee; }
+a { color: #8cf; }
+nav ul { columns: 4; padding: 0; list-style: none; }
+section { margin-top: 2rem; }
+pre { font-family: monospace; line-height: 1.1; overflow-x: auto; background: #000; padding: 1rem; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<nav>
+<ul>
+{{- range .Specimens}}
+<li><a href="#{{.ID}}">{{.Font}}</a></li>
+{{- end}}
+</ul>
+</nav>
+{{- range .Specimens}}
+<section id="{{.ID}}">
+<h2><a href="#{{.ID}}">{{.Font}}</a></h2>
+<pre>{{.Text}}</pre>
+</section>
+{{- end}}
+</body>
+</html>
+`))
+
+// gallerySpecimen is a specimen with the anchor it has on the page.
+type gallerySpecimen struct {
+	Specimen
+	ID string
+}
+
+// WriteGallery writes a self-contained HTML page showing each specimen under
+// its font name, with an index linking to every specimen's anchor. Names
+// whose anchors collide, such as "small slant" and "small-slant", get a
+// numeric suffix so every anchor is unique.
+func WriteGallery(w io.Writer, title string, specimens []Specimen) error {
+	used := make(map[string]bool, len(specimens))
+	page := make([]gallerySpecimen, len(specimens))
+	for i, s := range specimens {
+		id := s.Anchor()
+		for n := 2; used[id]; n++ {
+			id = fmt.Sprintf("%s-%d", s.Anchor(), n)
+		}
+		used[id] = true
+		page[i] = gallerySpecimen{s, id}
+	}
+	return galleryTemplate.Execute(w, struct {
+		Title     string
+		Specimens []gallerySpecimen
+	}{title, page})
+}
@@ -0,0 +1,47 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/phantompunk/fig/internal/assert"
+)
+
+func TestSpecimenAnchor(t *testing.T) {
+	assert.Equal(t, Specimen{Font: "slant"}.Anchor(), "font-slant")
+	assert.Equal(t, Specimen{Font: "DOS Rebel"}.Anchor(), "font-dos-rebel")
+	assert.Equal(t, Specimen{Font: "money-nw"}.Anchor(), "font-money-nw")
+	assert.Equal(t, Specimen{Font: "3d_diagonal!"}.Anchor(), "font-3d-diagonal")
+}
+
+func TestWriteGallery(t *testing.T) {
+	var b strings.Builder
+	err := WriteGallery(&b, "Hi & bye", []Specimen{
+		{Font: "small", Text: " _  <_>\n| || |"},
+		{Font: "dos rebel", Text: "###"},
+	})
+	assert.NilError(t, err)
+	out := b.String()
+
+	assert.True(t, strings.Contains(out, "<title>Hi &amp; bye</title>"))
+	assert.True(t, strings.Contains(out, `<a href="#font-small">small</a>`))
+	assert.True(t, strings.Contains(out, `<section id="font-dos-rebel">`))
+	assert.True(t, strings.Contains(out, "<pre> _  &lt;_&gt;\n| || |</pre>"))
+	assert.True(t, strings.Index(out, `id="font-small"`) < strings.Index(out, `id="font-dos-rebel"`))
+}
+
+func TestWriteGallery_uniqueAnchors(t *testing.T) {
+	var b strings.Builder
+	err := WriteGallery(&b, "fonts", []Specimen{
+		{Font: "small slant"},
+		{Font: "small-slant"},
+		{Font: "small_slant"},
+	})
+	assert.NilError(t, err)
+	out := b.String()
+
+	for _, id := range []string{"font-small-slant", "font-small-slant-2", "font-small-slant-3"} {
+		assert.Equal(t, strings.Count(out, `id="`+id+`"`), 1)
+	}
+	assert.True(t, strings.Contains(out, `<a href="#font-small-slant-2">small-slant</a>`))
+}
@@ -0,0 +1,486 @@
+package font
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Bitmap is a pixel font read from an X11 BDF or Linux console PSF file,
+// with every glyph placed in a cell of the font's full height.
+type Bitmap struct {
+	Height   int // pixel rows in every glyph cell
+	Ascent   int // rows above the baseline
+	Comments []string
+	Glyphs   map[rune]BitmapGlyph
+}
+
+// BitmapGlyph is one glyph of a Bitmap. Pixels has Height rows of Width
+// columns, top row first, and Width includes the glyph's spacing.
+type BitmapGlyph struct {
+	Width  int
+	Pixels [][]bool
+}
+
+// Bitmap fonts are rejected before any pixels are allocated when a glyph
+// would be wider or taller than maxBitmapPixels, or a PSF font claims more
+// than maxBitmapGlyphs glyphs. Console fonts stay well within both.
+const (
+	maxBitmapPixels = 256
+	maxBitmapGlyphs = 65536
+)
+
+// BitmapOptions controls how FromBitmap draws pixels.
+type BitmapOptions struct {
+	// Fill draws each set pixel. It is ignored when HalfBlocks is set.
+	Fill rune
+
+	// HalfBlocks draws each pair of pixel rows as one row of '▀', '▄' and
+	// '█' characters, halving the height of the font.
+	HalfBlocks bool
+}
+
+// ParseBDF reads an X11 Bitmap Distribution Format font. Glyph encodings are
+// taken as Unicode code points, which holds for ISO 10646 and ISO 8859-1
+// fonts; glyphs without an encoding are skipped.
+func ParseBDF(data []byte) (*Bitmap, error) {
+	b := &Bitmap{Glyphs: make(map[rune]BitmapGlyph)}
+	var descent, bboxH, bboxY int
+
+	scanner := &lineScanner{Scanner: bufio.NewScanner(bytes.NewReader(data))}
+	if !scanner.Scan() || !strings.HasPrefix(scanner.Text(), "STARTFONT") {
+		return nil, scanner.errorf(false, "not a BDF font, missing STARTFONT: %w", ErrUnsupportedFormat)
+	}
+
+	for scanner.Scan() {
+		keyword, rest, _ := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		args := strings.Fields(rest)
+		switch keyword {
+		case "FONTBOUNDINGBOX":
+			v, err := bdfPixels(args, 4)
+			if err != nil {
+				return nil, scanner.errorf(false, "FONTBOUNDINGBOX: %w", err)
+			}
+			bboxH, bboxY = v[1], v[3]
+		case "FONT_ASCENT", "FONT_DESCENT":
+			v, err := bdfPixels(args, 1)
+			if err != nil {
+				return nil, scanner.errorf(false, "%s: %w", keyword, err)
+			}
+			if keyword == "FONT_ASCENT" {
+				b.Ascent = v[0]
+			} else {
+				descent = v[0]
+			}
+		case "FONT", "COPYRIGHT", "NOTICE":
+			b.Comments = append(b.Comments, strings.Trim(rest, `"`))
+		case "STARTCHAR":
+			if b.Height == 0 {
+				if b.Ascent == 0 && descent == 0 {
+					b.Ascent, descent = bboxH+bboxY, -bboxY
+				}
+				b.Height = b.Ascent + descent
+				if b.Height <= 0 {
+					return nil, scanner.errorf(false, "font has no height")
+				}
+			}
+			code, g, err := b.bdfChar(scanner)
+			if err != nil {
+				return nil, err
+			}
+			if code >= 0 {
+				b.Glyphs[code] = g
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(b.Glyphs) == 0 {
+		return nil, fmt.Errorf("BDF font has no glyphs")
+	}
+	return b, nil
+}
+
+// bdfChar reads one glyph, from the line after STARTCHAR to ENDCHAR, and
+// places its bounding box in a cell of the font's height.
+func (b *Bitmap) bdfChar(scanner *lineScanner) (rune, BitmapGlyph, error) {
+	code := rune(-1)
+	var advance int
+	var bbx []int
+
+	for scanner.Scan() {
+		keyword, rest, _ := strings.Cut(strings.TrimSpace(scanner.Text()), " ")
+		args := strings.Fields(rest)
+		switch keyword {
+		case "ENCODING":
+			v, err := bdfInts(args[:min(len(args), 1)], 1)
+			if err != nil {
+				return 0, BitmapGlyph{}, scanner.errorf(false, "ENCODING: %w", err)
+			}
+			code = rune(v[0])
+		case "DWIDTH":
+			v, err := bdfPixels(args, 2)
+			if err != nil {
+				return 0, BitmapGlyph{}, scanner.errorf(false, "DWIDTH: %w", err)
+			}
+			advance = v[0]
+		case "BBX":
+			v, err := bdfPixels(args, 4)
+			if err != nil {
+				return 0, BitmapGlyph{}, scanner.errorf(false, "BBX: %w", err)
+			}
+			bbx = v
+		case "BITMAP":
+			if bbx == nil {
+				return 0, BitmapGlyph{}, scanner.errorf(false, "BITMAP before BBX")
+			}
+			w, h, x, y := bbx[0], bbx[1], bbx[2], bbx[3]
+			if w < 0 || h < 0 {
+				return 0, BitmapGlyph{}, scanner.errorf(false, "BBX has a negative size")
+			}
+			width := max(advance, x+w, 0)
+			g := BitmapGlyph{Width: width, Pixels: blankPixels(b.Height, width)}
+			top := b.Ascent - (y + h)
+			for row := range h {
+				if !scanner.Scan() {
+					return 0, BitmapGlyph{}, scanner.errorf(true, "unexpected end of file in BITMAP")
+				}
+				bits, err := hex.DecodeString(strings.TrimSpace(scanner.Text()))
+				if err != nil {
+					return 0, BitmapGlyph{}, scanner.errorf(false, "invalid BITMAP row: %w", err)
+				}
+				g.setRow(top+row, x, w, bits)
+			}
+			if !scanner.Scan() || strings.TrimSpace(scanner.Text()) != "ENDCHAR" {
+				return 0, BitmapGlyph{}, scanner.errorf(false, "expected ENDCHAR")
+			}
+			return code, g, nil
+		case "ENDCHAR":
+			return code, BitmapGlyph{Width: max(advance, 0), Pixels: blankPixels(b.Height, max(advance, 0))}, nil
+		}
+	}
+	return 0, BitmapGlyph{}, scanner.errorf(true, "unexpected end of file in STARTCHAR")
+}
+
+func bdfInts(args []string, n int) ([]int, error) {
+	if len(args) < n {
+		return nil, fmt.Errorf("expected %d numbers, got %d", n, len(args))
+	}
+	v := make([]int, n)
+	for i := range n {
+		var err error
+		if v[i], err = strconv.Atoi(args[i]); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+// bdfPixels is bdfInts for sizes and offsets in pixels, which must lie
+// within maxBitmapPixels of zero.
+func bdfPixels(args []string, n int) ([]int, error) {
+	v, err := bdfInts(args, n)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range v {
+		if d < -maxBitmapPixels || d > maxBitmapPixels {
+			return nil, fmt.Errorf("%d is out of range, at most %d pixels", d, maxBitmapPixels)
+		}
+	}
+	return v, nil
+}
+
+// PSF magic numbers, as they appear at the start of the file.
+var (
+	psf1Magic = []byte{0x36, 0x04}
+	psf2Magic = []byte{0x72, 0xb5, 0x4a, 0x86}
+	gzipMagic = []byte{0x1f, 0x8b}
+)
+
+// ParsePSF reads a Linux console font in PSF version 1 or 2, optionally
+// gzip-compressed as shipped in /usr/share/consolefonts, up to
+// DefaultLimits.MaxSize once decompressed. Glyphs are mapped
+// through the font's Unicode table, or by position when it has none.
+func ParsePSF(data []byte) (*Bitmap, error) {
+	if bytes.HasPrefix(data, gzipMagic) {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("reading compressed PSF font: %w", err)
+		}
+		// Stop one byte past the limit, as for zipped fonts.
+		if data, err = io.ReadAll(io.LimitReader(zr, int64(DefaultLimits.MaxSize)+1)); err != nil {
+			return nil, fmt.Errorf("reading compressed PSF font: %w", err)
+		}
+		if err := check("MaxSize", len(data), DefaultLimits.MaxSize); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		count, width, height, charSize int
+		glyphs, table                  []byte
+		utf8Table, hasTable            bool
+	)
+	switch {
+	case bytes.HasPrefix(data, psf2Magic):
+		if len(data) < 32 {
+			return nil, errors.New("PSF2 header is truncated")
+		}
+		var h [8]uint32
+		binary.Decode(data[:32], binary.LittleEndian, &h)
+		// Check the fields while they are unsigned, so none wraps around
+		// as an int.
+		headerSize, flags := h[2], h[3]
+		if headerSize > uint32(len(data)) || h[5] > uint32(len(data)) {
+			return nil, errors.New("PSF2 header is truncated")
+		}
+		if h[4] > maxBitmapGlyphs || h[6] > maxBitmapPixels || h[7] > maxBitmapPixels {
+			return nil, fmt.Errorf("PSF font is too large: %d glyphs of %dx%d pixels", h[4], h[7], h[6])
+		}
+		count, charSize, height, width = int(h[4]), int(h[5]), int(h[6]), int(h[7])
+		glyphs = data[headerSize:]
+		hasTable, utf8Table = flags&1 != 0, true
+	case bytes.HasPrefix(data, psf1Magic):
+		if len(data) < 4 {
+			return nil, errors.New("PSF1 header is truncated")
+		}
+		mode := data[2]
+		count, charSize, height, width = 256, int(data[3]), int(data[3]), 8
+		if mode&1 != 0 {
+			count = 512
+		}
+		glyphs = data[4:]
+		hasTable = mode&6 != 0
+	default:
+		return nil, fmt.Errorf("not a PSF font, bad magic number: %w", ErrUnsupportedFormat)
+	}
+
+	rowBytes := (width + 7) / 8
+	if width <= 0 || height <= 0 || charSize < rowBytes*height || count <= 0 {
+		return nil, fmt.Errorf("PSF font has invalid dimensions %dx%d", width, height)
+	}
+	if len(glyphs)/count < charSize {
+		return nil, fmt.Errorf("PSF font is truncated: %d glyphs of %d bytes, have %d bytes", count, charSize, len(glyphs))
+	}
+	table = glyphs[count*charSize:]
+
+	b := &Bitmap{Height: height, Ascent: height, Glyphs: make(map[rune]BitmapGlyph)}
+	for i := range count {
+		bits := glyphs[i*charSize : (i+1)*charSize]
+		g := BitmapGlyph{Width: width, Pixels: blankPixels(height, width)}
+		for row := range height {
+			g.setRow(row, 0, width, bits[row*rowBytes:(row+1)*rowBytes])
+		}
+
+		if !hasTable {
+			b.Glyphs[rune(i)] = g
+			continue
+		}
+		var codes []rune
+		codes, table = psfCodes(table, utf8Table)
+		for _, c := range codes {
+			b.Glyphs[c] = g
+		}
+	}
+	return b, nil
+}
+
+// psfCodes reads the Unicode table entry of one glyph: the code points it
+// draws, up to the end of the entry or the first multi-character sequence.
+func psfCodes(table []byte, utf8Table bool) ([]rune, []byte) {
+	var codes []rune
+	inSequence := false
+	for len(table) > 0 {
+		if utf8Table {
+			switch table[0] {
+			case 0xff:
+				return codes, table[1:]
+			case 0xfe:
+				inSequence, table = true, table[1:]
+				continue
+			}
+			r, size := utf8.DecodeRune(table)
+			if !inSequence {
+				codes = append(codes, r)
+			}
+			table = table[size:]
+			continue
+		}
+
+		if len(table) < 2 {
+			return codes, nil
+		}
+		v := binary.LittleEndian.Uint16(table)
+		table = table[2:]
+		switch v {
+		case 0xffff:
+			return codes, table
+		case 0xfffe:
+			inSequence = true
+		default:
+			if !inSequence {
+				codes = append(codes, rune(v))
+			}
+		}
+	}
+	return codes, table
+}
+
+func blankPixels(height, width int) [][]bool {
+	pixels := make([][]bool, height)
+	for i := range pixels {
+		pixels[i] = make([]bool, width)
+	}
+	return pixels
+}
+
+// setRow sets the pixels of row from the first w bits of bits, most
+// significant bit first, starting at column x. Pixels outside the glyph are
+// dropped.
+func (g BitmapGlyph) setRow(row, x, w int, bits []byte) {
+	if row < 0 || row >= len(g.Pixels) {
+		return
+	}
+	for col := range min(w, len(bits)*8) {
+		if bits[col/8]&(0x80>>(col%8)) != 0 && x+col >= 0 && x+col < g.Width {
+			g.Pixels[row][x+col] = true
+		}
+	}
+}
+
+// FromBitmap turns a bitmap font into a FIGfont named name. Pixel fonts carry
+// their own spacing, so the font uses full-width layout. Control characters
+// are dropped, required characters the bitmap lacks are left blank, and
+// characters past printable ASCII other than the German ones are stored as
+// code-tagged glyphs.
+func FromBitmap(b *Bitmap, name string, opts BitmapOptions) (*Font, error) {
+	fill := opts.Fill
+	if opts.HalfBlocks {
+		fill = '█'
+	}
+	if fill == 0 || fill == ' ' || fill == '\n' {
+		return nil, fmt.Errorf("invalid fill character %q", fill)
+	}
+	hardblank := '$'
+	if fill == hardblank {
+		hardblank = '~'
+	}
+
+	height, baseline := b.Height, b.Ascent
+	if opts.HalfBlocks {
+		height, baseline = (b.Height+1)/2, (b.Ascent+1)/2
+	}
+
+	codes := make([]rune, 0, len(b.Glyphs))
+	width := 0
+	for c, g := range b.Glyphs {
+		if c >= ' ' {
+			codes = append(codes, c)
+			width = max(width, g.Width)
+		}
+	}
+	slices.Sort(codes)
+
+	var tagged []CodeTag
+	deutsch := 0
+	for _, c := range codes {
+		switch {
+		case slices.Contains(deutschChars, c):
+			deutsch = len(deutschChars)
+		case c > 126:
+			tagged = append(tagged, CodeTag{Code: c})
+		}
+	}
+	if len(tagged) > 0 {
+		// Code tags follow the German characters, so those must be written.
+		deutsch = len(deutschChars)
+	}
+
+	comments := slices.Clone(b.Comments)
+	comments = append(comments, "Imported from a bitmap font by fig fonts import")
+	meta := Metadata{
+		signature:    "flf2a" + string(hardblank),
+		hardBlank:    hardblank,
+		height:       max(height, 1),
+		baseline:     max(baseline, 1),
+		maxLength:    width + 2,
+		oldLayout:    -1,
+		commentLines: len(comments),
+		fullLayout:   0,
+		codeTag:      len(tagged),
+		fields:       9,
+		comments:     strings.Join(comments, "\n"),
+	}
+	meta.layoutMode = parseLayoutMode(meta.oldLayout)
+	meta.smushMode = parseSmushMode(meta.fullLayout)
+
+	f := NewFigFont(name, meta)
+	f.deutsch = deutsch
+	f.tagged = tagged
+	blank := Glyph{lines: make([]string, meta.height)}
+	for c := rune(32); c <= 126; c++ {
+		f.glyphs[c] = blank
+	}
+	for _, c := range deutschChars[:deutsch] {
+		f.glyphs[c] = blank
+	}
+	for _, c := range codes {
+		g := b.Glyphs[c]
+		if opts.HalfBlocks {
+			f.glyphs[c] = newGlyph(halfBlockRows(g.Pixels))
+		} else {
+			f.glyphs[c] = newGlyph(fillRows(g.Pixels, fill))
+		}
+	}
+	return f, nil
+}
+
+func fillRows(pixels [][]bool, fill rune) []string {
+	rows := make([]string, len(pixels))
+	for i, row := range pixels {
+		var sb strings.Builder
+		for _, set := range row {
+			if set {
+				sb.WriteRune(fill)
+			} else {
+				sb.WriteByte(' ')
+			}
+		}
+		rows[i] = sb.String()
+	}
+	return rows
+}
+
+// halfBlockRows draws each pair of pixel rows as one row of half blocks.
+func halfBlockRows(pixels [][]bool) []string {
+	rows := make([]string, 0, (len(pixels)+1)/2)
+	for i := 0; i < len(pixels); i += 2 {
+		var sb strings.Builder
+		for col, top := range pixels[i] {
+			bottom := i+1 < len(pixels) && pixels[i+1][col]
+			switch {
+			case top && bottom:
+				sb.WriteRune('█')
+			case top:
+				sb.WriteRune('▀')
+			case bottom:
+				sb.WriteRune('▄')
+			default:
+				sb.WriteByte(' ')
+			}
+		}
+		rows = append(rows, sb.String())
+	}
+	return rows
+}
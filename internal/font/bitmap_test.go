@@ -0,0 +1,177 @@
+package font
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/phantompunk/fig/internal/assert"
+)
+
+const tinyBDF = `STARTFONT 2.1
+FONT -misc-tiny-medium-r-normal--4-40-75-75-c-40-iso10646-1
+SIZE 4 75 75
+FONTBOUNDINGBOX 4 4 0 -1
+STARTPROPERTIES 3
+FONT_ASCENT 3
+FONT_DESCENT 1
+COPYRIGHT "Public domain"
+ENDPROPERTIES
+CHARS 3
+STARTCHAR A
+ENCODING 65
+SWIDTH 500 0
+DWIDTH 4 0
+BBX 3 3 0 0
+BITMAP
+40
+A0
+E0
+ENDCHAR
+STARTCHAR g
+ENCODING 103
+DWIDTH 4 0
+BBX 3 3 0 -1
+BITMAP
+E0
+20
+C0
+ENDCHAR
+STARTCHAR smile
+ENCODING 9786
+DWIDTH 4 0
+BBX 2 1 1 1
+BITMAP
+C0
+ENDCHAR
+ENDFONT
+`
+
+func TestParseBDF(t *testing.T) {
+	b, err := ParseBDF([]byte(tinyBDF))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, b.Height, 4)
+	assert.Equal(t, b.Ascent, 3)
+	assert.Equal(t, len(b.Glyphs), 3)
+
+	f, err := FromBitmap(b, "tiny", BitmapOptions{Fill: '#'})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, slices.Equal(f.glyphs['A'].lines, []string{" #  ", "# # ", "### ", "    "}))
+	assert.True(t, slices.Equal(f.glyphs['g'].lines, []string{"    ", "### ", "  # ", "##  "}))
+	assert.True(t, slices.Equal(f.glyphs['☺'].lines, []string{"    ", " ## ", "    ", "    "}))
+	assert.Equal(t, f.Baseline(), 3)
+	assert.Equal(t, f.Comments(), "-misc-tiny-medium-r-normal--4-40-75-75-c-40-iso10646-1\nPublic domain\nImported from a bitmap font by fig fonts import")
+
+	// The font goes through the writer and reads back unchanged.
+	var buf bytes.Buffer
+	assert.NilError(t, Write(&buf, f))
+	assert.Equal(t, len(Lint(buf.Bytes())), 0)
+	assertSameFont(t, Must(Parse(buf.Bytes(), "tiny", "test")), f)
+	assert.Equal(t, Must(Parse(buf.Bytes(), "tiny", "test")).Render("Ag"), " #      \n# # ### \n###   # \n    ##  \n")
+
+	f, err = FromBitmap(b, "tiny", BitmapOptions{HalfBlocks: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, f.Height(), 2)
+	assert.True(t, slices.Equal(f.glyphs['A'].lines, []string{"▄▀▄ ", "▀▀▀ "}))
+	assert.True(t, slices.Equal(f.glyphs['g'].lines, []string{"▄▄▄ ", "▄▄▀ "}))
+
+	_, err = ParseBDF([]byte("STARTFONT 2.1\nFONTBOUNDINGBOX 4 4 0 -1\nSTARTCHAR A\nENCODING 65\nBBX 3 3 0 0\nBITMAP\nzz\n"))
+	var perr *ParseError
+	assert.True(t, errors.As(err, &perr))
+	assert.Equal(t, perr.Line, 7)
+}
+
+func TestParsePSF(t *testing.T) {
+	// PSF2 with a Unicode table: a 3x2 'x' and a block shared by '#' and a
+	// combining sequence, which is skipped.
+	var psf2 bytes.Buffer
+	psf2.Write(psf2Magic)
+	binary.Write(&psf2, binary.LittleEndian, []uint32{0, 32, 1, 2, 2, 2, 3})
+	psf2.Write([]byte{0xa0, 0x40, 0xe0, 0xe0})
+	psf2.WriteString("x\xff#\xfeá\xff")
+
+	b, err := ParsePSF(psf2.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, len(b.Glyphs), 2)
+	f := Must(FromBitmap(b, "psf2", BitmapOptions{Fill: '*'}))
+	assert.True(t, slices.Equal(f.glyphs['x'].lines, []string{"* *", " * "}))
+	assert.True(t, slices.Equal(f.glyphs['#'].lines, []string{"***", "***"}))
+
+	// Gzipped PSF1 without a table maps glyphs by position.
+	raw := append([]byte{0x36, 0x04, 0, 1}, make([]byte, 256)...)
+	raw[4+'A'] = 0xf0
+	var gz bytes.Buffer
+	zw := gzip.NewWriter(&gz)
+	zw.Write(raw)
+	zw.Close()
+
+	b, err = ParsePSF(gz.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, b.Height, 1)
+	f = Must(FromBitmap(b, "psf1", BitmapOptions{Fill: '#'}))
+	assert.Equal(t, f.glyphs['A'].lines[0], "####    ")
+	assert.Equal(t, len(f.CodeTags()), 129-7) // 127 to 255 but the German characters
+
+	_, err = ParsePSF(raw[:100])
+	assert.NotNil(t, err)
+}
+
+func TestParsePSF_badHeader(t *testing.T) {
+	psf2 := func(fields ...uint32) []byte {
+		var b bytes.Buffer
+		b.Write(psf2Magic)
+		binary.Write(&b, binary.LittleEndian, fields)
+		return b.Bytes()
+	}
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"short header", psf2(0, 32, 0)},
+		{"header past the end", psf2(0, 64, 0, 1, 1, 1, 1)},
+		{"huge glyphs", append(psf2(0, 36, 0, 0xffffffff, 0xffffffff, 8, 8), 0, 0, 0, 0)},
+		{"huge count", append(psf2(0, 32, 0, 0xffffffff, 8, 8, 8), make([]byte, 64)...)},
+		{"huge size", append(psf2(0, 32, 0, 1, 8, 8, 0xffffffff), make([]byte, 64)...)},
+		{"missing glyphs", append(psf2(0, 32, 0, 1000, 8, 8, 8), make([]byte, 64)...)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePSF(tt.data)
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestParseBDF_hugeGlyph(t *testing.T) {
+	tests := []struct {
+		line    string
+		errLine int
+	}{
+		{"DWIDTH 100000000 0", 6},
+		{"BBX 100000000 1 0 0", 6},
+		{"BBX 1 -5 0 0", 7},
+	}
+	for _, tt := range tests {
+		data := "STARTFONT 2.1\nFONTBOUNDINGBOX 4 4 0 -1\nSTARTCHAR A\nENCODING 65\nBBX 1 1 0 0\n" +
+			tt.line + "\nBITMAP\n80\nENDCHAR\n"
+		_, err := ParseBDF([]byte(data))
+		var perr *ParseError
+		if !errors.As(err, &perr) {
+			t.Fatalf("%s: got %v, want a ParseError", tt.line, err)
+		}
+		assert.Equal(t, perr.Line, tt.errLine)
+	}
+}
@@ -0,0 +1,494 @@
+package font
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// InputMode selects how a control file decodes input bytes into character
+// codes before any translation is applied.
+type InputMode uint8
+
+const (
+	ModeUTF8     InputMode = iota // u: UTF-8, the default
+	ModeISO2022                   // g: ISO 2022 with G0-G3 designations
+	ModeDBCS                      // b: double-byte, a high byte starts a pair
+	ModeHZ                        // h: HZ, ~{ and ~} switch GB 2312 on and off
+	ModeShiftJIS                  // j: Shift-JIS
+)
+
+// Charset is an ISO 2022 graphic character set: 94, 96 or 94x94 characters,
+// identified by the final byte of its designation sequence.
+type Charset struct {
+	Size  int // 94, 96 or 9494
+	Final byte
+}
+
+var (
+	charsetASCII   = Charset{Size: 94, Final: 'B'}
+	charsetLatin1R = Charset{Size: 96, Final: 'A'}
+)
+
+// code returns the character code for the bytes of one character in the set.
+// ASCII maps to itself and the Latin-1 right half to 0x80-0xff; any other
+// set puts its final byte in bits 16-23 above the 7-bit character value(s).
+func (cs Charset) code(b ...byte) rune {
+	var c rune
+	for _, x := range b {
+		c = c<<8 | rune(x&0x7f)
+	}
+	switch {
+	case cs == charsetASCII:
+		return c
+	case cs == charsetLatin1R:
+		return 0x80 | c
+	case cs.Size == 96:
+		return rune(cs.Final)<<16 | 0x80 | c
+	default:
+		return rune(cs.Final)<<16 | c
+	}
+}
+
+// translation maps the codes lo..hi onto to, to+1, ...
+type translation struct {
+	lo, hi, to rune
+}
+
+// Control is a parsed FIGlet control (.flc) file. Translations are grouped
+// into stages split by freeze commands; each stage sees the output of the one
+// before, and within a stage the first matching translation wins.
+type Control struct {
+	Name string
+
+	mode   InputMode
+	modeOK bool // mode was set by a command
+	g      [4]Charset
+	gl, gr int
+	stages [][]translation
+}
+
+// ParseControl parses the text of a FIGlet control file.
+func ParseControl(data []byte, name string) (*Control, error) {
+	c := &Control{
+		Name:   name,
+		g:      [4]Charset{charsetASCII, charsetLatin1R, charsetLatin1R, charsetLatin1R},
+		gl:     0,
+		gr:     1,
+		stages: [][]translation{nil},
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if err := c.command(text); err != nil {
+			return nil, &ParseError{Line: line, Err: err}
+		}
+	}
+	return c, nil
+}
+
+func (c *Control) command(text string) error {
+	if text == "" || text[0] == '#' || strings.HasPrefix(text, "flc2a") {
+		return nil
+	}
+
+	switch cmd, args := text[0], strings.TrimSpace(text[1:]); {
+	case cmd == 't':
+		return c.translate(args, readChar)
+	case cmd >= '0' && cmd <= '9' || cmd == '-':
+		return c.translate(text, readCode)
+	case cmd == 'f':
+		c.stages = append(c.stages, nil)
+	case cmd == 'u':
+		c.setMode(ModeUTF8)
+	case cmd == 'b':
+		c.setMode(ModeDBCS)
+	case cmd == 'h':
+		c.setMode(ModeHZ)
+	case cmd == 'j':
+		c.setMode(ModeShiftJIS)
+	case cmd == 'g':
+		c.setMode(ModeISO2022)
+		return c.designate(args)
+	default:
+		return fmt.Errorf("unknown control command %q", text)
+	}
+	return nil
+}
+
+func (c *Control) setMode(m InputMode) {
+	c.mode, c.modeOK = m, true
+}
+
+// translate parses "inchar outchar", where either may be a range "a-z", or
+// the "incode outcode" form of a line starting with a number.
+func (c *Control) translate(args string, read charReader) error {
+	from, rest, err := readRange(args, read)
+	if err != nil {
+		return err
+	}
+	to, rest, err := readRange(strings.TrimLeft(rest, " \t"), read)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(rest) != "" {
+		return fmt.Errorf("unexpected %q after translation", rest)
+	}
+	if from[1]-from[0] != to[1]-to[0] {
+		return fmt.Errorf("translation ranges differ in size")
+	}
+	last := len(c.stages) - 1
+	c.stages[last] = append(c.stages[last], translation{lo: from[0], hi: from[1], to: to[0]})
+	return nil
+}
+
+// designate parses the arguments of a g command: "L n" or "R n" invoke Gn
+// into the left or right half, "n 94|96|94x94 F" designates a set to Gn.
+// A bare g only selects ISO 2022 decoding.
+func (c *Control) designate(args string) error {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		return nil
+	}
+	switch fields[0] {
+	case "L", "R":
+		if len(fields) != 2 {
+			return fmt.Errorf("invalid g command %q", "g "+args)
+		}
+		n, err := gIndex(fields[1])
+		if err != nil {
+			return err
+		}
+		if fields[0] == "L" {
+			c.gl = n
+		} else {
+			c.gr = n
+		}
+		return nil
+	}
+
+	if len(fields) != 3 {
+		return fmt.Errorf("invalid g command %q", "g "+args)
+	}
+	n, err := gIndex(fields[0])
+	if err != nil {
+		return err
+	}
+	if len(fields[2]) != 1 {
+		return fmt.Errorf("invalid charset final byte %q", fields[2])
+	}
+	cs := Charset{Final: fields[2][0]}
+	switch fields[1] {
+	case "94":
+		cs.Size = 94
+	case "96":
+		cs.Size = 96
+	case "94x94":
+		cs.Size = 9494
+	default:
+		return fmt.Errorf("invalid charset size %q", fields[1])
+	}
+	c.g[n] = cs
+	return nil
+}
+
+func gIndex(s string) (int, error) {
+	if len(s) != 1 || s[0] < '0' || s[0] > '3' {
+		return 0, fmt.Errorf("invalid G set %q, expected 0-3", s)
+	}
+	return int(s[0] - '0'), nil
+}
+
+// charReader reads one character from the start of s and returns the rest.
+type charReader func(s string) (rune, string, error)
+
+// readRange reads a character or a range of characters from the start of s
+// and returns the rest of s.
+func readRange(s string, read charReader) ([2]rune, string, error) {
+	lo, rest, err := read(s)
+	if err != nil {
+		return [2]rune{}, "", err
+	}
+	if !strings.HasPrefix(rest, "-") {
+		return [2]rune{lo, lo}, rest, nil
+	}
+	hi, rest, err := read(rest[1:])
+	if err != nil {
+		return [2]rune{}, "", err
+	}
+	if hi < lo {
+		return [2]rune{}, "", fmt.Errorf("invalid range %q-%q", lo, hi)
+	}
+	return [2]rune{lo, hi}, rest, nil
+}
+
+// readChar reads one character from a control file: a literal character,
+// a backslash escape (\a \b \e \f \n \r \t \v, or any other character taken
+// literally, such as "\ " or "\-"), or a backslash followed by a decimal,
+// octal (leading 0) or hexadecimal (leading 0x) code.
+func readChar(s string) (rune, string, error) {
+	if s == "" {
+		return 0, "", fmt.Errorf("missing character")
+	}
+	if s[0] != '\\' {
+		r, size := utf8.DecodeRuneInString(s)
+		return r, s[size:], nil
+	}
+
+	s = s[1:]
+	if s == "" {
+		return 0, "", fmt.Errorf("missing character after \\")
+	}
+	if s[0] >= '0' && s[0] <= '9' || s[0] == '-' && len(s) > 1 && s[1] >= '0' && s[1] <= '9' {
+		return readCode(s)
+	}
+	escapes := map[byte]rune{'a': 7, 'b': 8, 'e': 27, 'f': 12, 'n': 10, 'r': 13, 't': 9, 'v': 11}
+	if r, ok := escapes[s[0]]; ok {
+		return r, s[1:], nil
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	return r, s[size:], nil
+}
+
+// readCode reads a numeric character code from the start of s.
+func readCode(s string) (rune, string, error) {
+	end := 0
+	if end < len(s) && s[end] == '-' {
+		end++
+	}
+	for end < len(s) && (s[end] >= '0' && s[end] <= '9' || s[end] >= 'a' && s[end] <= 'f' || s[end] >= 'A' && s[end] <= 'F' || s[end] == 'x' || s[end] == 'X') {
+		end++
+	}
+	code, err := strconv.ParseInt(s[:end], 0, 32)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid character code %q", s[:end])
+	}
+	return rune(code), s[end:], nil
+}
+
+// Apply decodes text according to the control's input mode and runs the
+// result through each translation stage. The codes returned may lie outside
+// Unicode, for fonts that tag glyphs with ISO 2022 or DBCS codes.
+func (c *Control) Apply(text string) []rune {
+	return c.translateAll(c.decode([]byte(text)))
+}
+
+func (c *Control) translateAll(codes []rune) []rune {
+	for _, stage := range c.stages {
+		for i, r := range codes {
+			for _, t := range stage {
+				if r >= t.lo && r <= t.hi {
+					codes[i] = t.to + r - t.lo
+					break
+				}
+			}
+		}
+	}
+	return codes
+}
+
+func (c *Control) decode(b []byte) []rune {
+	switch c.mode {
+	case ModeISO2022:
+		return c.decodeISO2022(b)
+	case ModeDBCS:
+		return decodeDBCS(b)
+	case ModeHZ:
+		return decodeHZ(b)
+	case ModeShiftJIS:
+		return decodeShiftJIS(b)
+	default:
+		return []rune(string(b))
+	}
+}
+
+// decodeISO2022 decodes b using the control's designations as the initial
+// state, honouring designation escapes, locking shifts (SI, SO, ESC n,
+// ESC o) and single shifts (SS2, SS3) found in the input.
+func (c *Control) decodeISO2022(b []byte) []rune {
+	g, gl, gr := c.g, c.gl, c.gr
+	single := -1 // set invoked by a single shift for the next character
+
+	var out []rune
+	for i := 0; i < len(b); i++ {
+		ch := b[i]
+		switch {
+		case ch == 0x1b && i+1 < len(b):
+			if n := iso2022Escape(b[i+1:], &g, &gl, &single); n > 0 {
+				i += n
+				continue
+			}
+			out = append(out, rune(ch))
+		case ch == 0x0e: // SO
+			gl = 1
+		case ch == 0x0f: // SI
+			gl = 0
+		case ch == 0x8e: // SS2
+			single = 2
+		case ch == 0x8f: // SS3
+			single = 3
+		case ch&0x7f < 0x20 || ch == 0x7f:
+			out = append(out, rune(ch))
+		default:
+			set := g[gl]
+			if ch >= 0x80 {
+				set = g[gr]
+			}
+			if single >= 0 {
+				set, single = g[single], -1
+			}
+			if set.Size == 94 && ch&0x7f == 0x20 {
+				out = append(out, ' ')
+				continue
+			}
+			if set.Size == 9494 && i+1 < len(b) {
+				out = append(out, set.code(ch, b[i+1]))
+				i++
+				continue
+			}
+			out = append(out, set.code(ch))
+		}
+	}
+	return out
+}
+
+// iso2022Escape applies the escape sequence whose bytes follow ESC and
+// returns how many of them it consumed, or 0 if it does not recognise them.
+func iso2022Escape(seq []byte, g *[4]Charset, gl, single *int) int {
+	intermediates94 := map[byte]int{'(': 0, ')': 1, '*': 2, '+': 3}
+	intermediates96 := map[byte]int{'-': 1, '.': 2, '/': 3}
+
+	switch seq[0] {
+	case 'n':
+		*gl = 2
+		return 1
+	case 'o':
+		*gl = 3
+		return 1
+	case 'N':
+		*single = 2
+		return 1
+	case 'O':
+		*single = 3
+		return 1
+	case '$':
+		if len(seq) >= 2 && seq[1] >= '@' && seq[1] <= 'B' {
+			g[0] = Charset{Size: 9494, Final: seq[1]}
+			return 2
+		}
+		if len(seq) >= 3 {
+			if n, ok := intermediates94[seq[1]]; ok {
+				g[n] = Charset{Size: 9494, Final: seq[2]}
+				return 3
+			}
+		}
+		return 0
+	}
+	if len(seq) < 2 {
+		return 0
+	}
+	if n, ok := intermediates94[seq[0]]; ok {
+		g[n] = Charset{Size: 94, Final: seq[1]}
+		return 2
+	}
+	if n, ok := intermediates96[seq[0]]; ok {
+		g[n] = Charset{Size: 96, Final: seq[1]}
+		return 2
+	}
+	return 0
+}
+
+// decodeDBCS combines a byte at or above 0x80 with the byte after it.
+func decodeDBCS(b []byte) []rune {
+	var out []rune
+	for i := 0; i < len(b); i++ {
+		if b[i] >= 0x80 && i+1 < len(b) {
+			out = append(out, rune(b[i])<<8|rune(b[i+1]))
+			i++
+			continue
+		}
+		out = append(out, rune(b[i]))
+	}
+	return out
+}
+
+// decodeShiftJIS combines Shift-JIS lead bytes with their trail byte; other
+// bytes, including half-width katakana, are single characters.
+func decodeShiftJIS(b []byte) []rune {
+	var out []rune
+	for i := 0; i < len(b); i++ {
+		lead := b[i] >= 0x81 && b[i] <= 0x9f || b[i] >= 0xe0 && b[i] <= 0xfc
+		if lead && i+1 < len(b) {
+			out = append(out, rune(b[i])<<8|rune(b[i+1]))
+			i++
+			continue
+		}
+		out = append(out, rune(b[i]))
+	}
+	return out
+}
+
+// decodeHZ decodes HZ text: "~{" starts GB 2312 pairs, returned in their
+// EUC form with the high bits set, "~}" returns to ASCII, "~~" is a tilde
+// and "~" before a newline joins the lines.
+func decodeHZ(b []byte) []rune {
+	var out []rune
+	gb := false
+	for i := 0; i < len(b); i++ {
+		if b[i] == '~' && i+1 < len(b) {
+			switch b[i+1] {
+			case '{':
+				gb = true
+				i++
+				continue
+			case '}':
+				gb = false
+				i++
+				continue
+			case '~':
+				out = append(out, '~')
+				i++
+				continue
+			case '\n':
+				i++
+				continue
+			}
+		}
+		if gb && b[i] > 0x20 && b[i] < 0x7f && i+1 < len(b) {
+			out = append(out, (rune(b[i])<<8|rune(b[i+1]))|0x8080)
+			i++
+			continue
+		}
+		out = append(out, rune(b[i]))
+	}
+	return out
+}
+
+// Chain combines controls the way figlet combines several -C options: the
+// translation stages run in order, and the input mode and ISO 2022 state
+// come from the last control that sets them.
+func Chain(controls ...*Control) *Control {
+	if len(controls) == 1 {
+		return controls[0]
+	}
+
+	names := make([]string, len(controls))
+	out := &Control{
+		g:  [4]Charset{charsetASCII, charsetLatin1R, charsetLatin1R, charsetLatin1R},
+		gr: 1,
+	}
+	for i, c := range controls {
+		names[i] = c.Name
+		out.stages = append(out.stages, c.stages...)
+		if c.modeOK {
+			out.mode, out.modeOK = c.mode, true
+			out.g, out.gl, out.gr = c.g, c.gl, c.gr
+		}
+	}
+	out.Name = strings.Join(names, "+")
+	return out
+}
@@ -0,0 +1,116 @@
+package font
+
+import (
+	"testing"
+
+	"github.com/phantompunk/fig/internal/assert"
+)
+
+func mustControl(t *testing.T, text string) *Control {
+	t.Helper()
+	c, err := ParseControl([]byte(text), "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestControl_Translate(t *testing.T) {
+	c := mustControl(t, "flc2a\n# comment\n\nt a-z A-Z\nt \\  _\n")
+	assert.Equal(t, string(c.Apply("hi there!")), "HI_THERE!")
+}
+
+func TestControl_FirstMatchWinsWithinStage(t *testing.T) {
+	c := mustControl(t, "t a-m n-z\nt n-z a-m\n")
+	assert.Equal(t, string(c.Apply("uryyb")), "hello")
+}
+
+func TestControl_FreezeChainsStages(t *testing.T) {
+	// Without the freeze a->b would win and b->c would never see its output.
+	c := mustControl(t, "t a b\nf\nt b c\n")
+	assert.Equal(t, string(c.Apply("ab")), "cc")
+}
+
+func TestControl_NumericAndEscapedCodes(t *testing.T) {
+	c := mustControl(t, "65 0x42\nt \\0x63-\\0144 \\101-\\102\nt \\- \\e\n")
+	assert.Equal(t, string(c.Apply("Acd-")), "Bef\x1b")
+}
+
+func TestControl_Errors(t *testing.T) {
+	for _, text := range []string{
+		"t a-z A-Y",
+		"t a",
+		"x",
+		"g 4 94 B",
+		"g 0 95 B",
+	} {
+		_, err := ParseControl([]byte("flc2a\n"+text), "bad")
+		perr, ok := err.(*ParseError)
+		assert.True(t, ok)
+		assert.Equal(t, perr.Line, 2)
+	}
+}
+
+func TestControl_ISO2022(t *testing.T) {
+	c := mustControl(t, "g 1 96 B\ngR 1\n")
+	assert.Equal(t, c.mode, ModeISO2022)
+
+	// GR is a Latin-2 (96 'B') set: 0xB1 maps to its final byte plus 0x80|0x31.
+	got := c.Apply("a\xb1")
+	assert.Equal(t, got[0], 'a')
+	assert.Equal(t, got[1], rune('B')<<16|0xb1)
+
+	// ESC $ B designates JIS X 0208 to G0; ESC ( B returns to ASCII.
+	got = mustControl(t, "g").Apply("\x1b$B\x30\x21\x1b(Bz")
+	assert.Equal(t, len(got), 2)
+	assert.Equal(t, got[0], rune('B')<<16|0x3021)
+	assert.Equal(t, got[1], 'z')
+
+	// SO shifts to G1 (Latin-1 right half by default), SI shifts back.
+	got = mustControl(t, "g").Apply("\x0e\x44\x0fD")
+	assert.Equal(t, string(got), "ÄD")
+}
+
+func TestControl_MultibyteModes(t *testing.T) {
+	got := mustControl(t, "b").Apply("a\xb0\xa1")
+	assert.Equal(t, len(got), 2)
+	assert.Equal(t, got[1], rune(0xb0a1))
+
+	got = mustControl(t, "j").Apply("\x82\xa0\xb1")
+	assert.Equal(t, len(got), 2)
+	assert.Equal(t, got[0], rune(0x82a0))
+	assert.Equal(t, got[1], rune(0xb1))
+
+	got = mustControl(t, "h").Apply("a~{\x30\x21~}~~b")
+	assert.Equal(t, len(got), 4)
+	assert.Equal(t, got[1], rune(0xb0a1))
+	assert.Equal(t, string(got[2:]), "~b")
+}
+
+func TestChain(t *testing.T) {
+	rot13 := mustControl(t, "t a-m n-z\nt n-z a-m\n")
+	upper := mustControl(t, "t a-z A-Z\n")
+	dbcs := mustControl(t, "b")
+
+	c := Chain(rot13, upper)
+	assert.Equal(t, string(c.Apply("uryyb")), "HELLO")
+	assert.Equal(t, c.Name, "test+test")
+
+	assert.Equal(t, Chain(dbcs, upper).mode, ModeDBCS)
+	assert.Equal(t, Chain(upper, dbcs).mode, ModeDBCS)
+}
+
+func TestRegistry_Control(t *testing.T) {
+	c, err := NewRegistry(BundledLoader()).Control("upper")
+	assert.NilError(t, err)
+	assert.Equal(t, string(c.Apply("fig")), "FIG")
+
+	_, err = NewRegistry(BundledLoader()).Control("missing")
+	assert.True(t, err != nil)
+
+	names, err := BundledLoader().List()
+	assert.NilError(t, err)
+	for _, name := range names {
+		assert.True(t, name != "upper")
+	}
+}
@@ -0,0 +1,189 @@
+package font
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// hardblankCandidates are tried, in order, as the hardblank of a font
+// converted to FLF when its own hardblank is a space or not ASCII.
+var hardblankCandidates = []rune{'$', '~', '^', '`', '&', '%', '@', '#'}
+
+// Convert returns a copy of f in the given format with the header signature
+// rewritten to match and the code tag count corrected.
+//
+// FLF fonts are read byte by byte by figlet, so converting to FLF replaces
+// every character outside printable ASCII in the glyph rows with an ASCII
+// substitute, such as '#' for a full block or '+' for a box corner, and
+// picks an ASCII hardblank if needed. Each glyph that changes is described in
+// the returned warnings. Converting to TLF keeps the glyphs and only decodes
+// Latin-1 rows, found in older FIGfonts, as UTF-8.
+func Convert(f *Font, to Format) (*Font, []string) {
+	meta := f.metadata
+	glyphs := make(GlyphDict, len(f.glyphs))
+	var warnings []string
+
+	switch to {
+	case FormatTLF:
+		meta.comments = latin1ToUTF8(meta.comments)
+		for c, g := range f.glyphs {
+			lines := make([]string, len(g.lines))
+			for i, line := range g.lines {
+				lines[i] = latin1ToUTF8(line)
+			}
+			glyphs[c] = newGlyph(lines)
+		}
+		meta.signature = "tlf2a" + string(meta.hardBlank)
+
+	default:
+		hardblank := meta.hardBlank
+		if hardblank <= ' ' || hardblank > 0x7f {
+			hardblank = pickHardblank(f)
+			warnings = append(warnings, fmt.Sprintf("hardblank %q replaced with %q", meta.hardBlank, hardblank))
+		}
+		for _, c := range f.Runes() {
+			g := f.glyphs[c]
+			lines := make([]string, len(g.lines))
+			var replaced []string
+			for i, line := range g.lines {
+				lines[i] = strings.Map(func(r rune) rune {
+					switch {
+					case r == meta.hardBlank:
+						return hardblank
+					case r >= ' ' && r < 0x7f:
+						return r
+					}
+					sub := asciiSubstitute(r)
+					if note := fmt.Sprintf("%q with %q", r, sub); !slices.Contains(replaced, note) {
+						replaced = append(replaced, note)
+					}
+					return sub
+				}, latin1ToUTF8(line))
+			}
+			glyphs[c] = newGlyph(lines)
+			if len(replaced) > 0 {
+				warnings = append(warnings, fmt.Sprintf("character %s: replaced %s", describeCode(c), strings.Join(replaced, ", ")))
+			}
+		}
+		meta.hardBlank = hardblank
+		meta.signature = "flf2a" + string(hardblank)
+	}
+
+	if meta.fields > 8 {
+		meta.codeTag = len(f.tagged)
+	}
+
+	out := NewFigFont(f.name, meta)
+	out.source = f.source
+	out.format = to
+	out.glyphs = glyphs
+	out.deutsch = f.deutsch
+	out.tagged = slices.Clone(f.tagged)
+	return out, warnings
+}
+
+// newGlyph returns a glyph of the given rows, measuring its width.
+func newGlyph(lines []string) Glyph {
+	width := 0
+	for _, line := range lines {
+		width = max(width, len(line))
+	}
+	return Glyph{lines: lines, width: width}
+}
+
+// pickHardblank returns the first candidate hardblank that no glyph uses.
+func pickHardblank(f *Font) rune {
+	for _, hb := range hardblankCandidates {
+		used := false
+		for _, g := range f.glyphs {
+			for _, line := range g.lines {
+				if strings.ContainsRune(line, hb) {
+					used = true
+				}
+			}
+		}
+		if !used {
+			return hb
+		}
+	}
+	return hardblankCandidates[0]
+}
+
+// latin1ToUTF8 decodes s as Latin-1 when it is not valid UTF-8.
+func latin1ToUTF8(s string) string {
+	if utf8.ValidString(s) {
+		return s
+	}
+	runes := make([]rune, len(s))
+	for i := range len(s) {
+		runes[i] = rune(s[i])
+	}
+	return string(runes)
+}
+
+// asciiSubstitutes maps characters common in TOIlet fonts to the ASCII
+// character that best keeps the shape of a glyph.
+var asciiSubstitutes = map[rune]rune{
+	// Latin-1 punctuation and symbols.
+	'¡': '!', '¢': 'c', '£': 'L', '¤': 'o', '¥': 'Y', '¦': '|', '§': 'S',
+	'¨': '"', '©': 'C', 'ª': 'a', '«': '<', '¬': '-', '®': 'R', '¯': '-',
+	'°': 'o', '±': '+', '²': '2', '³': '3', '´': '\'', 'µ': 'u', '¶': 'P',
+	'·': '.', '¸': ',', '¹': '1', 'º': 'o', '»': '>', '¿': '?',
+
+	// Quotes and dashes.
+	'‘': '\'', '’': '\'', '‚': ',', '“': '"', '”': '"', '„': '"',
+	'–': '-', '—': '-', '…': '.', '•': '*',
+
+	// Box drawing.
+	'─': '-', '━': '-', '┄': '-', '┅': '-', '┈': '-', '┉': '-', '╌': '-',
+	'╍': '-', '═': '=', '╴': '-', '╶': '-', '╸': '-', '╺': '-', '╼': '-',
+	'╾': '-', '│': '|', '┃': '|', '┆': '|', '┇': '|', '┊': '|', '┋': '|',
+	'╎': '|', '╏': '|', '║': '|', '╵': '|', '╷': '|', '╹': '|', '╻': '|',
+	'╽': '|', '╿': '|', '╭': '.', '╮': '.', '╯': '\'', '╰': '\'',
+	'╱': '/', '╲': '\\', '╳': 'X',
+
+	// Block elements and geometric shapes.
+	'▀': '"', '▁': '_', '▂': '_', '▃': '_', '▄': ',', '▅': 'm', '▆': 'm',
+	'▇': 'M', '█': '#', '▌': '[', '▐': ']', '░': ':', '▒': '%', '▓': '#',
+	'▔': '-', '▕': '|', '▏': '|', '▖': '.', '▗': '.', '▘': '\'', '▝': '\'',
+	'■': '#', '□': 'o', '▪': '#', '▫': 'o', '●': 'o', '○': 'o', '◆': '*',
+	'⊕': 'o', '⊖': 'o', '⊗': 'x', '⊘': 'o', '⊙': 'o', '⊚': 'o', '⊛': '*',
+	'⊜': 'o', '⓪': '0',
+}
+
+// asciiDecomposed lists the ASCII look-alike of each Latin-1 character from
+// U+00C0 to U+00FF, mostly the base letter of an accented one.
+const asciiDecomposed = "AAAAAAECEEEEIIIIDNOOOOOxOUUUUYPBaaaaaaeceeeeiiiidnooooo/ouuuuypy"
+
+// asciiSubstitute returns the ASCII character that best replaces r in a
+// glyph row.
+func asciiSubstitute(r rune) rune {
+	if sub, ok := asciiSubstitutes[r]; ok {
+		return sub
+	}
+	switch {
+	case r >= 0xc0 && r <= 0xff:
+		return rune(asciiDecomposed[r-0xc0])
+	case r >= 0xff01 && r <= 0xff5e: // fullwidth forms
+		return r - 0xff01 + '!'
+	case r == 0x3000: // ideographic space
+		return ' '
+	case r >= 0x2460 && r <= 0x2468: // circled digits
+		return r - 0x2460 + '1'
+	case r >= 0x24b6 && r <= 0x24cf: // circled capital letters
+		return r - 0x24b6 + 'A'
+	case r >= 0x24d0 && r <= 0x24e9: // circled small letters
+		return r - 0x24d0 + 'a'
+	case r >= 0x2500 && r <= 0x257f: // remaining box drawing: corners, tees, crosses
+		return '+'
+	case r == 0x2800: // blank braille pattern
+		return ' '
+	case r > 0x2800 && r <= 0x28ff: // braille patterns
+		return ':'
+	case r >= 0x2580 && r <= 0x25ff: // remaining blocks and shapes
+		return '#'
+	}
+	return '?'
+}
@@ -0,0 +1,92 @@
+package font
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/phantompunk/fig/internal/assert"
+)
+
+// tlfFont builds a height-1 TOIlet font with the multibyte hardblank '¤'
+// and endmark '●', where 'A' is drawn with a full block.
+func tlfFont() []byte {
+	var b strings.Builder
+	b.WriteString("tlf2a¤ 1 1 5 0 0 0 0 1\n")
+	for c := rune(32); c <= 126; c++ {
+		if c == 'A' {
+			b.WriteString("█¤█●●\n")
+			continue
+		}
+		b.WriteString(string(c) + "●●\n")
+	}
+	for _, c := range deutschChars {
+		b.WriteString(string(c) + "●●\n")
+	}
+	b.WriteString("0x2588  FULL BLOCK\n█●●\n")
+	return []byte(b.String())
+}
+
+func TestParse_TLF(t *testing.T) {
+	f := Must(Parse(tlfFont(), "blocks", "test"))
+	assert.Equal(t, f.Format(), FormatTLF)
+	assert.Equal(t, f.Hardblank(), '¤')
+	assert.Equal(t, f.Height(), 1)
+	assert.Equal(t, f.glyphs['A'].lines[0], "█¤█")
+	assert.Equal(t, f.glyphs[0x2588].lines[0], "█")
+
+	// TOIlet fonts such as circle.tlf use a space as the hardblank and end
+	// rows with a single endmark.
+	f = Must(Parse([]byte("tlf2a  1 1 3 -1 0\n"+strings.Repeat("x@\n", 95)), "spaced", "test"))
+	assert.Equal(t, f.Hardblank(), ' ')
+	assert.Equal(t, f.MaxLength(), 3)
+	assert.Equal(t, f.glyphs['A'].lines[0], "x")
+}
+
+func TestConvert_TLFToFLF(t *testing.T) {
+	f := Must(Parse(tlfFont(), "blocks", "test"))
+	flf, warnings := Convert(f, FormatFLF)
+
+	assert.Equal(t, flf.Format(), FormatFLF)
+	assert.Equal(t, flf.Hardblank(), '$')
+	assert.Equal(t, flf.glyphs['A'].lines[0], "#$#")
+	assert.Equal(t, strings.Join(warnings, "\n"), strings.Join([]string{
+		"hardblank '¤' replaced with '$'",
+		"character 'A' (65): replaced '█' with '#'",
+		"character 'Ä' (196): replaced 'Ä' with 'A'",
+		"character 'Ö' (214): replaced 'Ö' with 'O'",
+		"character 'Ü' (220): replaced 'Ü' with 'U'",
+		"character 'ß' (223): replaced 'ß' with 'B'",
+		"character 'ä' (228): replaced 'ä' with 'a'",
+		"character 'ö' (246): replaced 'ö' with 'o'",
+		"character 'ü' (252): replaced 'ü' with 'u'",
+		"character '█' (9608): replaced '█' with '#'",
+	}, "\n"))
+
+	var buf bytes.Buffer
+	assert.NilError(t, Write(&buf, flf))
+	assert.True(t, strings.HasPrefix(buf.String(), "flf2a$ 1 1 5 0 0 0 0 1\n"))
+	for _, r := range buf.String() {
+		if r > 0x7f {
+			t.Fatalf("converted font contains %q", r)
+		}
+	}
+	assertSameFont(t, Must(Parse(buf.Bytes(), "blocks", "test")), flf)
+}
+
+func TestConvert_FLFToTLF(t *testing.T) {
+	// A Latin-1 hardblank, as in pyramid.flf, becomes its UTF-8 encoding.
+	data := "flf2a\x81 1 1 3 0 0\n" + strings.Repeat("a\x81@@\n", 95)
+	f := Must(Parse([]byte(data), "latin1", "test"))
+	tlf, warnings := Convert(f, FormatTLF)
+	assert.Equal(t, len(warnings), 0)
+
+	var buf bytes.Buffer
+	assert.NilError(t, Write(&buf, tlf))
+	assert.True(t, strings.HasPrefix(buf.String(), "tlf2a\u0081 1 1 3 0 0\n"))
+
+	got := Must(Parse(buf.Bytes(), "latin1", "test"))
+	assert.Equal(t, got.Format(), FormatTLF)
+	assert.Equal(t, got.Hardblank(), '\u0081')
+	assert.Equal(t, got.glyphs['A'].lines[0], "a\u0081")
+}
@@ -0,0 +1,145 @@
+package font
+
+import (
+	"cmp"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strings"
+)
+
+// glyphDigests returns a hash of every glyph's rows. Hardblanks are hashed
+// as one marker, so fonts that differ only in their hardblank character
+// still hash alike.
+func (f *Font) glyphDigests() map[rune]uint64 {
+	digests := make(map[rune]uint64, len(f.glyphs))
+	for c, g := range f.glyphs {
+		h := fnv.New64a()
+		for _, line := range g.lines {
+			h.Write([]byte(strings.ReplaceAll(line, string(f.metadata.hardBlank), "\x00")))
+			h.Write([]byte{'\n'})
+		}
+		digests[c] = h.Sum64()
+	}
+	return digests
+}
+
+// Fingerprint returns a hash of everything that decides how the font
+// renders: its glyphs and its layout. Fonts with equal fingerprints render
+// any text the same, whatever their names, comments or hardblanks.
+func (f *Font) Fingerprint() string {
+	digests := f.glyphDigests()
+	h := fnv.New128a()
+	// Hash the parsed layout rather than f.rules, whose funcs would only
+	// print as code addresses.
+	fmt.Fprintf(h, "%+v %+v\n", f.metadata.layoutMode, f.metadata.smushMode)
+	for _, c := range f.Runes() {
+		fmt.Fprintf(h, "%d %x\n", c, digests[c])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Similarity returns the share of characters, across both fonts, that have
+// the same glyph in a and b: 1 when every glyph matches and 0 when none do.
+// Layout is not compared.
+func Similarity(a, b *Font) float64 {
+	return similarity(a.glyphDigests(), b.glyphDigests())
+}
+
+func similarity(a, b map[rune]uint64) float64 {
+	same, union := 0, len(b)
+	for c, d := range a {
+		if e, ok := b[c]; !ok {
+			union++
+		} else if d == e {
+			same++
+		}
+	}
+	if union == 0 {
+		return 0
+	}
+	return float64(same) / float64(union)
+}
+
+// Duplicate is a pair of fonts that render alike. Exact duplicates have
+// equal fingerprints; near duplicates share at least the requested share of
+// glyphs. Canonical is the name to keep and Alias the other.
+type Duplicate struct {
+	Canonical  string
+	Alias      string
+	Exact      bool
+	Similarity float64
+}
+
+// FindDuplicates compares every pair of fonts and returns those whose glyph
+// similarity is at least threshold, exact duplicates first, then by
+// similarity and name.
+func FindDuplicates(fonts []*Font, threshold float64) []Duplicate {
+	digests := make([]map[rune]uint64, len(fonts))
+	prints := make([]string, len(fonts))
+	for i, f := range fonts {
+		digests[i] = f.glyphDigests()
+		prints[i] = f.Fingerprint()
+	}
+
+	var dupes []Duplicate
+	for i, a := range fonts {
+		for j := i + 1; j < len(fonts); j++ {
+			b := fonts[j]
+			if a.Height() != b.Height() {
+				continue
+			}
+			d := Duplicate{Exact: prints[i] == prints[j], Similarity: similarity(digests[i], digests[j])}
+			if !d.Exact && d.Similarity < threshold {
+				continue
+			}
+			d.Canonical, d.Alias = a.Name(), b.Name()
+			if preferName(d.Alias, d.Canonical) < 0 {
+				d.Canonical, d.Alias = d.Alias, d.Canonical
+			}
+			dupes = append(dupes, d)
+		}
+	}
+	slices.SortFunc(dupes, func(a, b Duplicate) int {
+		if a.Exact != b.Exact {
+			if a.Exact {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Or(
+			cmp.Compare(b.Similarity, a.Similarity),
+			cmp.Compare(a.Canonical, b.Canonical),
+			cmp.Compare(a.Alias, b.Alias),
+		)
+	})
+	return dupes
+}
+
+// preferName orders names by how well they serve as the canonical name:
+// ones that need no quoting on a command line, then shorter ones, then
+// alphabetically.
+func preferName(a, b string) int {
+	return cmp.Or(
+		cmp.Compare(strings.Count(a, " "), strings.Count(b, " ")),
+		cmp.Compare(len(a), len(b)),
+		cmp.Compare(a, b),
+	)
+}
+
+// AutoAliases maps the alias of every exact duplicate to its canonical
+// name. Fonts that are all identical map to the one name preferName ranks
+// first, as it pairs with each of them.
+func AutoAliases(dupes []Duplicate) map[string]string {
+	aliases := make(map[string]string)
+	for _, d := range dupes {
+		if !d.Exact {
+			continue
+		}
+		if c, ok := aliases[d.Alias]; !ok || preferName(d.Canonical, c) < 0 {
+			aliases[d.Alias] = d.Canonical
+		}
+	}
+	return aliases
+}
@@ -0,0 +1,133 @@
+package font
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/phantompunk/fig/assets"
+	"github.com/phantompunk/fig/internal/assert"
+)
+
+// letters returns a one-row font whose glyph for each of A to Z is the
+// letter itself, with the given glyph rows replaced.
+func letters(name string, replace map[rune]string) *Font {
+	f := Blank(name, 1)
+	for c := 'A'; c <= 'Z'; c++ {
+		row, ok := replace[c]
+		if !ok {
+			row = string(c)
+		}
+		f.SetGlyph(c, []string{row})
+	}
+	return f
+}
+
+func TestFingerprint(t *testing.T) {
+	a := letters("a", map[rune]string{'A': "A$"})
+	b := letters("b", map[rune]string{'A': "A~"})
+	b.metadata.hardBlank = '~'
+	b.metadata.comments = "a copy"
+	assert.Equal(t, a.Fingerprint(), b.Fingerprint())
+
+	c := letters("c", map[rune]string{'A': "a$"})
+	assert.True(t, a.Fingerprint() != c.Fingerprint())
+
+	d := a.Clone()
+	d.rules = nil
+	d.metadata.layoutMode.FullWidth = true
+	assert.True(t, a.Fingerprint() != d.Fingerprint())
+
+	// Kerning and smushing with the same glyphs render differently, as do
+	// different smushing rules, however the rules' funcs print.
+	kern, smush := a.Clone(), a.Clone()
+	kern.metadata.layoutMode = LayoutMode{Kerning: true}
+	smush.metadata.layoutMode = LayoutMode{Smushing: true}
+	assert.True(t, kern.Fingerprint() != smush.Fingerprint())
+	bigX := smush.Clone()
+	bigX.metadata.smushMode.BigX = true
+	assert.True(t, smush.Fingerprint() != bigX.Fingerprint())
+}
+
+func TestSimilarity(t *testing.T) {
+	a := letters("a", nil)
+	assert.Equal(t, Similarity(a, a), 1.0)
+
+	// 4 of the 95+7 glyphs differ.
+	b := letters("b", map[rune]string{'A': "a", 'B': "b", 'C': "c", 'D': "d"})
+	assert.Equal(t, Similarity(a, b), float64(102-4)/102)
+}
+
+func TestFindDuplicates(t *testing.T) {
+	fonts := []*Font{
+		letters("small caps", nil),
+		letters("smallcaps", nil),
+		letters("smcaps", nil),
+		letters("near", map[rune]string{'Q': "q"}),
+		letters("other", map[rune]string{'A': "a", 'B': "b", 'C': "c", 'D': "d", 'E': "e", 'F': "f", 'G': "g", 'H': "h", 'I': "i", 'J': "j", 'K': "k"}),
+		Blank("tall", 2),
+	}
+
+	var got []string
+	for _, d := range FindDuplicates(fonts, 0.95) {
+		got = append(got, d.Canonical+"="+d.Alias)
+	}
+	assert.Equal(t, strings.Join(got, " "), "smallcaps=small caps smcaps=small caps smcaps=smallcaps "+
+		"near=small caps near=smallcaps near=smcaps")
+
+	aliases := AutoAliases(FindDuplicates(fonts, 0.95))
+	assert.Equal(t, len(aliases), 2)
+	assert.Equal(t, aliases["small caps"], "smcaps")
+	assert.Equal(t, aliases["smallcaps"], "smcaps")
+}
+
+func TestRegistry_Aliases(t *testing.T) {
+	r := NewRegistry(newStubLoader(map[string][]byte{
+		"smallcaps":  minimalFLF(),
+		"small caps": minimalFLF(),
+		"other":      []byte("flf2a$ 1 1 2 0 0\n"),
+	}))
+	r.SetAliases(map[string]string{"small caps": "smallcaps", "old": "smallcaps", "loop": "old"})
+
+	f, err := r.Get("old")
+	assert.NilError(t, err)
+	assert.Equal(t, f.Name(), "smallcaps")
+	g, err := r.Get("small caps")
+	assert.NilError(t, err)
+	assert.True(t, f == g)
+	assert.Equal(t, r.Canonical("old"), "smallcaps")
+	assert.Equal(t, r.Canonical("loop"), "loop")
+
+	// Aliases and fonts that fail to parse are left out.
+	dupes, err := r.Duplicates(0.5)
+	assert.NilError(t, err)
+	assert.Equal(t, len(dupes), 0)
+}
+
+func TestLoadAliases(t *testing.T) {
+	aliases, err := LoadAliases([]byte(`fonts:
+  - name: "smallcaps"
+    tags: ["small"]
+    aliases: ["small caps", "small-caps"]
+  - name: "slant"
+`))
+	assert.NilError(t, err)
+	assert.Equal(t, len(aliases), 2)
+	assert.Equal(t, aliases["small-caps"], "smallcaps")
+}
+
+func TestBundledAliases_ExactDuplicates(t *testing.T) {
+	// An alias replaces a font of its own name, so fonts.yaml may only
+	// alias fonts that render exactly alike.
+	aliases, err := LoadAliases(assets.FontsYAML)
+	assert.NilError(t, err)
+	r := NewRegistry(BundledLoader())
+	for alias, canonical := range aliases {
+		a, err := r.Get(alias)
+		assert.NilError(t, err)
+		c, err := r.Get(canonical)
+		assert.NilError(t, err)
+		if a != nil && c != nil && a.Fingerprint() != c.Fingerprint() {
+			t.Errorf("fonts.yaml aliases %q to %q, which renders differently", alias, canonical)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package font
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Blank returns an empty font of the given height for designing from
+// scratch. Every required character is a blank glyph, the hardblank is '$'
+// and characters are kerned together like most hand-drawn fonts.
+func Blank(name string, height int) *Font {
+	meta, err := parseHeader(fmt.Sprintf("flf2a$ %d %d 2 0 0 0 %d 0", height, height, BitKern))
+	if err != nil {
+		panic(err) // the header above is always valid
+	}
+
+	f := NewFigFont(name, meta)
+	f.deutsch = len(deutschChars)
+	blank := Glyph{lines: make([]string, height)}
+	for c := rune(32); c <= 126; c++ {
+		f.glyphs[c] = blank
+	}
+	for _, c := range deutschChars {
+		f.glyphs[c] = blank
+	}
+	return f
+}
+
+// Clone returns a copy of f that can be edited without affecting f, which
+// may be shared through a FontRegistry.
+func (f *Font) Clone() *Font {
+	c := *f
+	c.glyphs = maps.Clone(f.glyphs)
+	c.tagged = slices.Clone(f.tagged)
+	return &c
+}
+
+// Glyph returns the rows of the glyph for char, with hardblanks as written
+// in the font, and whether the font defines it.
+func (f *Font) Glyph(char rune) ([]string, bool) {
+	g, ok := f.glyphs[char]
+	return slices.Clone(g.lines), ok
+}
+
+// SetGlyph replaces the glyph for char with rows, padded or cut to the
+// font's height and padded with spaces to the width of the widest row. A
+// character that is not a required FIGfont character becomes a code-tagged
+// glyph, and the header's max length grows to fit the widest row.
+func (f *Font) SetGlyph(char rune, rows []string) {
+	lines := make([]string, f.metadata.height)
+	copy(lines, rows)
+	width := 0
+	for _, line := range lines {
+		width = max(width, utf8.RuneCountInString(line))
+	}
+	for i, line := range lines {
+		lines[i] = line + strings.Repeat(" ", width-utf8.RuneCountInString(line))
+	}
+	f.metadata.maxLength = max(f.metadata.maxLength, width+2)
+
+	_, exists := f.glyphs[char]
+	f.glyphs[char] = newGlyph(lines)
+	if exists || char >= 32 && char <= 126 {
+		return
+	}
+	// Code tags follow the German characters, so both make them required.
+	f.deutsch = len(deutschChars)
+	if slices.Contains(deutschChars, char) {
+		return
+	}
+	f.tagged = append(f.tagged, CodeTag{Code: char})
+	if f.metadata.fields > 8 {
+		f.metadata.codeTag = len(f.tagged)
+	}
+}
@@ -0,0 +1,43 @@
+package font
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+
+	"github.com/phantompunk/fig/internal/assert"
+)
+
+func TestBlank_SetGlyph(t *testing.T) {
+	f := Blank("mascot", 2)
+	assert.Equal(t, f.Height(), 2)
+	assert.Equal(t, len(f.Runes()), 95+7)
+
+	f.SetGlyph('A', []string{"/\\", "|$|"})
+	f.SetGlyph('☺', []string{"o"})
+	rows, ok := f.Glyph('☺')
+	assert.True(t, ok)
+	assert.True(t, slices.Equal(rows, []string{"o", " "}))
+	assert.Equal(t, f.MaxLength(), 5)
+	assert.Equal(t, f.CodeTagCount(), 1)
+	// The hardblank keeps the two A's from being kerned together.
+	assert.Equal(t, f.Render("AA"), "/\\ /\\ \n| || |\n")
+
+	var buf bytes.Buffer
+	assert.NilError(t, Write(&buf, f))
+	assert.Equal(t, len(Lint(buf.Bytes())), 0)
+	assertSameFont(t, Must(Parse(buf.Bytes(), "mascot", "test")), f)
+}
+
+func TestFont_Clone(t *testing.T) {
+	f := Must(Parse(minimalFLF(), "mini", "test"))
+	c := f.Clone()
+	c.SetGlyph('A', []string{"#"})
+	c.SetGlyph('é', []string{"e"})
+
+	rows, _ := f.Glyph('A')
+	assert.Equal(t, rows[0], " ")
+	_, ok := f.Glyph('é')
+	assert.False(t, ok)
+	assert.Equal(t, len(f.CodeTags()), 0)
+}
@@ -0,0 +1,48 @@
+package font
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrFontNotFound matches, with errors.Is, the error for a font that no
+	// loader has. The error is a *NotFoundError naming the loaders searched.
+	ErrFontNotFound = errors.New("font not found")
+
+	// ErrUnsupportedFormat matches, with errors.Is, the error for data that
+	// is not in a font format fig reads, such as a file without a flf2a or
+	// tlf2a header.
+	ErrUnsupportedFormat = errors.New("unsupported font format")
+)
+
+// NotFoundError reports a font that none of the searched loaders has.
+type NotFoundError struct {
+	Name        string
+	Loaders     []string // the loaders searched, in order; empty for a path
+	Suggestions []string // available fonts with similar names, closest first
+}
+
+func (e *NotFoundError) Error() string {
+	msg := fmt.Sprintf("font %q not found", e.Name)
+	if len(e.Loaders) > 0 {
+		msg += " in " + strings.Join(e.Loaders, ", ")
+	}
+	if len(e.Suggestions) == 0 {
+		return msg
+	}
+	quoted := make([]string, len(e.Suggestions))
+	for i, s := range e.Suggestions {
+		quoted[i] = strconv.Quote(s)
+	}
+	last := len(quoted) - 1
+	if last == 0 {
+		return msg + "; did you mean " + quoted[0] + "?"
+	}
+	return msg + "; did you mean " + strings.Join(quoted[:last], ", ") + " or " + quoted[last] + "?"
+}
+
+// Is reports whether target is ErrFontNotFound.
+func (e *NotFoundError) Is(target error) bool { return target == ErrFontNotFound }
@@ -0,0 +1,75 @@
+package font
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/phantompunk/fig/internal/assert"
+)
+
+func TestGet_NotFoundError(t *testing.T) {
+	r := NewRegistry(FSLoader{FS: fstest.MapFS{}, Name: "first"}, FSLoader{FS: fstest.MapFS{}, Name: "second"})
+
+	_, err := r.Get("missing")
+	assert.True(t, errors.Is(err, ErrFontNotFound))
+	var nerr *NotFoundError
+	if !errors.As(err, &nerr) {
+		t.Fatalf("got %v, want a NotFoundError", err)
+	}
+	assert.Equal(t, nerr.Name, "missing")
+	assert.Equal(t, len(nerr.Loaders), 2)
+	assert.Equal(t, err.Error(), `font "missing" not found in first, second`)
+
+	_, err = NewRegistry(FileLoader{}).Get(filepath.Join(t.TempDir(), "missing.flf"))
+	assert.True(t, errors.Is(err, ErrFontNotFound))
+}
+
+// deniedFS fails every open with a permission error.
+type deniedFS struct{}
+
+func (deniedFS) Open(name string) (fs.File, error) {
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrPermission}
+}
+
+func TestGet_LoaderError(t *testing.T) {
+	// A loader that fails for another reason stops the search rather than
+	// being passed over as if it lacked the font.
+	r := NewRegistry(FSLoader{FS: deniedFS{}, Name: "denied"}, FSLoader{FS: fstest.MapFS{"mini.flf": {Data: minimalFLF()}}, Name: "second"})
+	_, err := r.Get("mini")
+	assert.True(t, errors.Is(err, fs.ErrPermission))
+
+	// So does a bundled font over the registry's size limit.
+	z, err := NewZipLoader(zipOf(t, map[string][]byte{"mini.flf": minimalFLF()}, "mini.flf"), "bundle.zip")
+	assert.NilError(t, err)
+	r = NewRegistry(z, FSLoader{FS: fstest.MapFS{"mini.flf": {Data: minimalFLF()}}, Name: "second"})
+	r.SetLimits(Limits{MaxSize: 100})
+	var lerr *LimitError
+	_, err = r.Get("mini")
+	assert.True(t, errors.As(err, &lerr))
+}
+
+func TestParseError_Char(t *testing.T) {
+	// The 'A' glyph, character 65, is cut short.
+	_, err := Parse(minimalFLF()[:len("flf2a$ 1 1 2 0 0\n")+33*4+1], "short", "test")
+	var perr *ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("got %v, want a ParseError", err)
+	}
+	assert.Equal(t, perr.Char, 'A')
+
+	_, err = ParseLimited(minimalFLF(), "mini", "test", Limits{MaxGlyphs: 3})
+	assert.True(t, errors.As(err, &perr))
+	assert.Equal(t, perr.Char, '#')
+}
+
+func TestParse_UnsupportedFormat(t *testing.T) {
+	for _, data := range []string{"\x89PNG\r\n\x1a\n", "STARTFONT 2.1\n", "flf"} {
+		_, err := Parse([]byte(data), "bad", "test")
+		assert.True(t, errors.Is(err, ErrUnsupportedFormat))
+	}
+	_, err := ParsePSF([]byte("not a psf font"))
+	assert.True(t, errors.Is(err, ErrUnsupportedFormat))
+}
@@ -1,10 +1,23 @@
 package font
 
+import "slices"
+
 type Font struct {
 	name     string
+	source   string
+	format   Format
 	metadata Metadata
 	glyphs   GlyphDict
 	rules    []SmushRule
+	deutsch  int       // German glyphs present after ASCII, 0 to 7
+	tagged   []CodeTag // code-tagged glyphs in file order
+}
+
+// CodeTag describes a code-tagged glyph: its character code and the free
+// text that followed the code on the tag line.
+type CodeTag struct {
+	Code    rune
+	Comment string
 }
 
 // LoadFont loads a FIGlet font by name.
@@ -22,12 +35,44 @@ func Must(t *Font, err error) *Font {
 
 // Name returns the name of the font.
 func (f *Font) Name() string     { return f.name }
+func (f *Font) Source() string   { return f.source }
+func (f *Font) Format() Format   { return f.format }
 func (f *Font) Height() int      { return f.metadata.height }
+func (f *Font) Baseline() int    { return f.metadata.baseline }
 func (f *Font) Hardblank() rune  { return f.metadata.hardBlank }
 func (f *Font) MaxLength() int   { return f.metadata.maxLength }
 func (f *Font) Rules() []SmushRule { return f.rules }
 func (f *Font) IsFullWidth() bool  { return f.metadata.layoutMode.FullWidth }
 
+// Header fields as written in the font file.
+func (f *Font) OldLayout() int      { return f.metadata.oldLayout }
+func (f *Font) FullLayout() int     { return f.metadata.fullLayout }
+func (f *Font) PrintDirection() int { return f.metadata.printDirection }
+func (f *Font) CodeTagCount() int   { return f.metadata.codeTag }
+
+// HasFullLayout reports whether the header carries the optional full_layout
+// field, rather than it being derived from old_layout.
+func (f *Font) HasFullLayout() bool { return f.metadata.fields > 7 }
+
+// Comments returns the font's comment block, lines separated by newlines.
+func (f *Font) Comments() string { return f.metadata.comments }
+
+// DeutschCount returns how many of the seven German glyphs the font defines.
+func (f *Font) DeutschCount() int { return f.deutsch }
+
+// CodeTags returns the font's code-tagged glyphs in file order.
+func (f *Font) CodeTags() []CodeTag { return slices.Clone(f.tagged) }
+
+// Runes returns every character the font has a glyph for, in ascending order.
+func (f *Font) Runes() []rune {
+	runes := make([]rune, 0, len(f.glyphs))
+	for r := range f.glyphs {
+		runes = append(runes, r)
+	}
+	slices.Sort(runes)
+	return runes
+}
+
 // GlyphRunes returns the glyph for char as a 2D rune slice ready for the
 // canvas. Regular spaces are converted to 0 (transparent); hardblanks and all
 // other characters are kept as-is.
@@ -132,7 +177,7 @@ type Glyph struct {
 type GlyphDict map[rune]Glyph
 
 type Metadata struct {
-	signature      string
+	signature      string // header up to and including the hardblank, as written
 	hardBlank      rune
 	height         int
 	baseline       int
@@ -142,6 +187,7 @@ type Metadata struct {
 	printDirection int
 	fullLayout     int
 	codeTag        int
+	fields         int // header fields after flf2a, counting the hardblank
 	comments       string
 	smushMode      SmushMode
 	layoutMode     LayoutMode
@@ -0,0 +1,170 @@
+package font
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"slices"
+	"sync"
+
+	"github.com/phantompunk/fig/assets"
+)
+
+// FontInfo describes a font from its header and glyph coverage, which is
+// enough to list and lay out fonts without parsing their glyphs.
+type FontInfo struct {
+	Name           string   `json:"name"`
+	Format         Format   `json:"format"`
+	Source         string   `json:"-"`
+	Height         int      `json:"height"`
+	Baseline       int      `json:"baseline"`
+	MaxLength      int      `json:"max_length"`
+	OldLayout      int      `json:"old_layout"`
+	FullLayout     int      `json:"full_layout"`
+	PrintDirection int      `json:"print_direction"`
+	CommentLines   int      `json:"comment_lines"`
+	Glyphs         int      `json:"glyphs"`    // characters with a glyph
+	Deutsch        int      `json:"deutsch"`   // German glyphs, 0 to 7
+	CodeTags       int      `json:"code_tags"` // code-tagged glyphs
+	Tags           []string `json:"tags,omitempty"`
+	Size           int      `json:"size,omitempty"` // bytes, once unzipped; 0 when unknown
+
+	// Coverage lists the characters with a glyph as inclusive ranges of
+	// code points, in order.
+	Coverage [][2]rune `json:"coverage"`
+}
+
+// Covers reports whether the font has a glyph for c.
+func (i FontInfo) Covers(c rune) bool {
+	_, found := slices.BinarySearchFunc(i.Coverage, c, func(r [2]rune, c rune) int {
+		switch {
+		case r[1] < c:
+			return -1
+		case r[0] > c:
+			return 1
+		}
+		return 0
+	})
+	return found
+}
+
+// coverage groups the sorted runes into ranges of consecutive code points.
+func coverage(runes []rune) [][2]rune {
+	var ranges [][2]rune
+	for _, c := range runes {
+		if n := len(ranges); n > 0 && ranges[n-1][1] == c-1 {
+			ranges[n-1][1] = c
+			continue
+		}
+		ranges = append(ranges, [2]rune{c, c})
+	}
+	return ranges
+}
+
+// Info describes f. Tags come from fonts.yaml rather than the font, and the
+// size from the file, so both are left empty.
+func (f *Font) Info() FontInfo {
+	return FontInfo{
+		Name:           f.name,
+		Format:         f.format,
+		Source:         f.source,
+		Height:         f.metadata.height,
+		Baseline:       f.metadata.baseline,
+		MaxLength:      f.metadata.maxLength,
+		OldLayout:      f.metadata.oldLayout,
+		FullLayout:     f.metadata.fullLayout,
+		PrintDirection: f.metadata.printDirection,
+		CommentLines:   f.metadata.commentLines,
+		Glyphs:         len(f.glyphs),
+		Deutsch:        f.deutsch,
+		CodeTags:       len(f.tagged),
+		Coverage:       coverage(f.Runes()),
+	}
+}
+
+// IndexedLoader is implemented by loaders that can describe their fonts
+// without parsing them, from an index generated ahead of time.
+type IndexedLoader interface {
+	// Info returns the indexed description of the named font, and whether
+	// the index has it.
+	Info(name string) (FontInfo, bool)
+}
+
+// BuildIndex parses every font in dir of fsys, as FSLoader lists them, and
+// returns their descriptions sorted by name, tagged from tags. Fonts that
+// fail to parse are left out, so loading them still reports the error.
+func BuildIndex(fsys fs.FS, dir string, tags TagMap) ([]FontInfo, error) {
+	l := FSLoader{FS: fsys, Dir: dir}
+	names, err := l.List()
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(names)
+
+	infos := make([]FontInfo, 0, len(names))
+	for _, name := range names {
+		data, format, err := l.Load(name)
+		if err != nil {
+			return nil, err
+		}
+		f, err := Parse(data, name, "")
+		if err != nil {
+			continue
+		}
+		f.format = format
+		info := f.Info()
+		info.Tags = tags[name]
+		if data, err := unzipFont(data, 0); err == nil {
+			info.Size = len(data)
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// WriteIndex writes infos as a JSON array with one font per line, which
+// keeps diffs of the generated file readable.
+func WriteIndex(w io.Writer, infos []FontInfo) error {
+	var b bytes.Buffer
+	b.WriteString("[\n")
+	for i, info := range infos {
+		line, err := json.Marshal(info)
+		if err != nil {
+			return err
+		}
+		b.Write(line)
+		if i < len(infos)-1 {
+			b.WriteByte(',')
+		}
+		b.WriteByte('\n')
+	}
+	b.WriteString("]\n")
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// ReadIndex parses an index written by WriteIndex.
+func ReadIndex(data []byte) ([]FontInfo, error) {
+	var infos []FontInfo
+	if err := json.Unmarshal(data, &infos); err != nil {
+		return nil, fmt.Errorf("parsing font index: %w", err)
+	}
+	return infos, nil
+}
+
+// bundledIndex maps the bundled fonts to their descriptions in the index
+// generated into assets. A missing or broken index leaves it empty, which
+// only costs speed: every font is then parsed to describe it.
+var bundledIndex = sync.OnceValue(func() map[string]FontInfo {
+	infos, err := ReadIndex(assets.FontIndex)
+	if err != nil {
+		return nil
+	}
+	index := make(map[string]FontInfo, len(infos))
+	for _, info := range infos {
+		index[info.Name] = info
+	}
+	return index
+})
@@ -0,0 +1,124 @@
+package font
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/phantompunk/fig/assets"
+	"github.com/phantompunk/fig/internal/assert"
+)
+
+func TestBundledIndex_UpToDate(t *testing.T) {
+	tags, err := LoadTagMap(assets.FontsYAML)
+	assert.NilError(t, err)
+	infos, err := BuildIndex(assets.FontFS, ".", tags)
+	assert.NilError(t, err)
+
+	var buf bytes.Buffer
+	assert.NilError(t, WriteIndex(&buf, infos))
+	if !bytes.Equal(buf.Bytes(), assets.FontIndex) {
+		t.Fatal("assets/index.json is out of date, run go generate ./assets")
+	}
+
+	got, err := ReadIndex(buf.Bytes())
+	assert.NilError(t, err)
+	assert.Equal(t, len(got), len(infos))
+	assert.Equal(t, got[0].Format, infos[0].Format)
+}
+
+func TestRegistry_Info(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "slant.flf"), minimalFLF(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	r := NewRegistry(DirLoader{Dir: dir}, BundledLoader())
+
+	// Bundled fonts are described from the index without being parsed.
+	info, err := r.Info("standard")
+	assert.NilError(t, err)
+	assert.Equal(t, info.Height, 6)
+	assert.Equal(t, info.Source, "embedded")
+	_, loaded := r.cache.Load("standard")
+	assert.False(t, loaded)
+
+	f, err := r.Get("standard")
+	assert.NilError(t, err)
+	parsed := f.Info()
+	assert.Equal(t, parsed.Height, info.Height)
+	assert.Equal(t, parsed.MaxLength, info.MaxLength)
+	assert.Equal(t, parsed.FullLayout, info.FullLayout)
+	assert.Equal(t, parsed.Glyphs, info.Glyphs)
+	assert.Equal(t, fmt.Sprint(parsed.Coverage), fmt.Sprint(info.Coverage))
+	assert.True(t, info.Covers('A'))
+	assert.True(t, info.Covers('Ä'))
+	assert.False(t, info.Covers('☺'))
+
+	// A font directory shadows the bundled font of the same name.
+	info, err = r.Info("slant")
+	assert.NilError(t, err)
+	assert.Equal(t, info.Height, 1)
+	assert.Equal(t, info.Source, dir)
+
+	_, err = r.Info("no such font")
+	assert.True(t, errors.Is(err, ErrFontNotFound))
+}
+
+func TestRegistry_Info_limits(t *testing.T) {
+	// Indexed fonts are held to the registry's limits, as Get holds them.
+	for _, limits := range []Limits{{MaxHeight: 5}, {MaxGlyphs: 200}, {MaxCommentLines: 5}, {MaxSize: 1000}} {
+		r := NewRegistry(BundledLoader())
+		r.SetLimits(limits)
+		var lerr *LimitError
+		_, err := r.Info("standard")
+		if !errors.As(err, &lerr) {
+			t.Errorf("%+v: got %v, want a LimitError", limits, err)
+		}
+	}
+
+	r := NewRegistry(BundledLoader())
+	r.SetLimits(Limits{MaxHeight: 6})
+	info, err := r.Info("standard")
+	assert.NilError(t, err)
+	assert.Equal(t, info.Source, "embedded")
+	_, loaded := r.cache.Load("standard")
+	assert.False(t, loaded)
+}
+
+func TestRegistry_Info_loadsOnce(t *testing.T) {
+	stub := newStubLoader(map[string][]byte{"mini": minimalFLF()})
+	r := NewRegistry(stub, BundledLoader())
+
+	info, err := r.Info("mini")
+	assert.NilError(t, err)
+	assert.Equal(t, fmt.Sprint(info.Coverage), "[[32 126]]")
+	_, err = r.Get("mini")
+	assert.NilError(t, err)
+	assert.Equal(t, stub.loadCount["mini"], 1)
+}
+
+// BenchmarkFontHeights measures what the TUI does at startup: look up the
+// height of every bundled font.
+func BenchmarkFontHeights(b *testing.B) {
+	names, err := BundledLoader().List()
+	if err != nil {
+		b.Fatal(err)
+	}
+	loaders := map[string]FontLoader{
+		"index": BundledLoader(),
+		"parse": EmbedLoader{FS: assets.FontFS, Dir: "."},
+	}
+	for _, name := range []string{"index", "parse"} {
+		b.Run(name, func(b *testing.B) {
+			for b.Loop() {
+				r := NewRegistry(loaders[name])
+				for _, font := range names {
+					r.Info(font)
+				}
+			}
+		})
+	}
+}
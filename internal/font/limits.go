@@ -0,0 +1,69 @@
+package font
+
+import "fmt"
+
+// Limits caps the resources a font may claim, so fonts from untrusted
+// sources cannot make the parser or renderer allocate without bound. The
+// renderer sizes its canvas from the height and max length, so those two
+// matter most. A zero field means no limit.
+type Limits struct {
+	MaxHeight       int // rows per glyph
+	MaxLength       int // the header's max_length, the widest row plus endmarks
+	MaxCommentLines int
+	MaxGlyphs       int // required and code-tagged glyphs together
+	MaxSize         int // bytes of font data, after decompressing a zipped font
+}
+
+// DefaultLimits are used by Parse, LoadFile and new registries. They are
+// well above what any FIGlet or TOIlet font in the wild needs.
+var DefaultLimits = Limits{
+	MaxHeight:       256,
+	MaxLength:       1024,
+	MaxCommentLines: 10000,
+	MaxGlyphs:       65536,
+	MaxSize:         16 << 20,
+}
+
+// LimitError reports a font that exceeds one of its Limits. Header values
+// are reported inside a ParseError for line 1.
+type LimitError struct {
+	Limit string // the exceeded field of Limits, such as "MaxHeight"
+	Value int
+	Max   int
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("font exceeds %s: %d is more than %d", e.Limit, e.Value, e.Max)
+}
+
+// check returns a LimitError when value is over a nonzero max.
+func check(limit string, value, max int) error {
+	if max > 0 && value > max {
+		return &LimitError{Limit: limit, Value: value, Max: max}
+	}
+	return nil
+}
+
+// header checks the header values of meta against l.
+func (l Limits) header(meta Metadata) error {
+	if err := check("MaxHeight", meta.height, l.MaxHeight); err != nil {
+		return err
+	}
+	if err := check("MaxLength", meta.maxLength, l.MaxLength); err != nil {
+		return err
+	}
+	return check("MaxCommentLines", meta.commentLines, l.MaxCommentLines)
+}
+
+// info checks an indexed description of a font against l, as parsing the
+// font would.
+func (l Limits) info(info FontInfo) error {
+	meta := Metadata{height: info.Height, maxLength: info.MaxLength, commentLines: info.CommentLines}
+	if err := l.header(meta); err != nil {
+		return err
+	}
+	if err := check("MaxGlyphs", info.Glyphs, l.MaxGlyphs); err != nil {
+		return err
+	}
+	return check("MaxSize", info.Size, l.MaxSize)
+}
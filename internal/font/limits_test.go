@@ -0,0 +1,103 @@
+package font
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/phantompunk/fig/internal/assert"
+)
+
+func TestParseLimited_Header(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		limits Limits
+		limit  string
+	}{
+		{"height", "flf2a$ 300 1 2 0 0", DefaultLimits, "MaxHeight"},
+		{"max length", "flf2a$ 1 1 5000 0 0", DefaultLimits, "MaxLength"},
+		{"comment lines", "flf2a$ 1 1 2 0 3", Limits{MaxCommentLines: 2}, "MaxCommentLines"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := []byte(tt.header + "\n" + string(minimalFLF()[len("flf2a$ 1 1 2 0 0\n"):]))
+			_, err := ParseLimited(data, "big", "test", tt.limits)
+
+			var perr *ParseError
+			var lerr *LimitError
+			if !errors.As(err, &perr) || !errors.As(err, &lerr) {
+				t.Fatalf("got %v, want a LimitError in a ParseError", err)
+			}
+			assert.Equal(t, perr.Line, 1)
+			assert.Equal(t, lerr.Limit, tt.limit)
+		})
+	}
+}
+
+func TestParseLimited_Glyphs(t *testing.T) {
+	_, err := ParseLimited(minimalFLF(), "mini", "test", Limits{MaxGlyphs: 50})
+	var lerr *LimitError
+	if !errors.As(err, &lerr) {
+		t.Fatalf("got %v, want a LimitError", err)
+	}
+	assert.Equal(t, lerr.Limit, "MaxGlyphs")
+	assert.Equal(t, lerr.Value, 51)
+
+	_, err = ParseLimited(minimalFLF(), "mini", "test", Limits{MaxGlyphs: 95})
+	assert.NilError(t, err)
+}
+
+func TestParseLimited_Size(t *testing.T) {
+	data := minimalFLF()
+	limits := Limits{MaxSize: len(data) - 1}
+
+	var lerr *LimitError
+	_, err := ParseLimited(data, "mini", "test", limits)
+	assert.True(t, errors.As(err, &lerr))
+
+	// A zipped font is held to the limit once decompressed.
+	zipped := zipOf(t, map[string][]byte{"mini.flf": data}, "mini.flf")
+	assert.True(t, len(zipped) < limits.MaxSize)
+	_, err = ParseLimited(zipped, "mini", "test", limits)
+	assert.True(t, errors.As(err, &lerr))
+
+	_, err = ParseLimited(zipped, "mini", "test", Limits{})
+	assert.NilError(t, err)
+}
+
+func TestRegistry_SetLimits(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mini.flf")
+	if err := os.WriteFile(path, minimalFLF(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRegistry(FileLoader{}, newStubLoader(map[string][]byte{"mini": minimalFLF()}))
+	r.SetLimits(Limits{MaxSize: 100})
+
+	var lerr *LimitError
+	_, err := r.Get("mini")
+	assert.True(t, errors.As(err, &lerr))
+	_, err = r.Get(path)
+	assert.True(t, errors.As(err, &lerr))
+	assert.True(t, strings.HasPrefix(err.Error(), path+":"))
+
+	_, err = NewRegistry(newStubLoader(map[string][]byte{"mini": minimalFLF()})).Get("mini")
+	assert.NilError(t, err)
+}
+
+func TestZipLoader_limited(t *testing.T) {
+	z, err := NewZipLoader(zipOf(t, map[string][]byte{"mini.flf": minimalFLF()}, "mini.flf"), "bundle.zip")
+	assert.NilError(t, err)
+
+	// The entry is read no further than the registry's limit.
+	var lerr *LimitError
+	_, _, err = z.loadLimited("mini", 100)
+	assert.True(t, errors.As(err, &lerr))
+	assert.Equal(t, lerr.Value, 101)
+
+	_, err = NewRegistry(z).Get("mini")
+	assert.NilError(t, err)
+}
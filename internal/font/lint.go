@@ -0,0 +1,294 @@
+package font
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Problem is one issue found by Lint, on a 1-based line of the font file.
+type Problem struct {
+	Line int
+	Msg  string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%d: %s", p.Line, p.Msg)
+}
+
+// linter walks a font file line by line, collecting every problem instead of
+// stopping at the first like Parse does.
+type linter struct {
+	lines     []string
+	next      int // index of the next unread line
+	problems  []Problem
+	height    int
+	maxLength int
+	hardblank rune
+}
+
+func (l *linter) report(line int, format string, args ...any) {
+	l.problems = append(l.problems, Problem{Line: line, Msg: fmt.Sprintf(format, args...)})
+}
+
+// Lint checks FIGfont data against the FIGfont 2 specification and returns
+// every problem found, in file order: header fields, comment and row counts,
+// endmarks, row widths against the max length, hardblank use, duplicate
+// code tags and missing required characters.
+func Lint(data []byte) []Problem {
+	data, err := unzipFont(data, DefaultLimits.MaxSize)
+	if err != nil {
+		return []Problem{{Line: 1, Msg: err.Error()}}
+	}
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	l := &linter{lines: strings.Split(strings.TrimSuffix(text, "\n"), "\n")}
+
+	header, ok := l.header()
+	if !ok {
+		return l.problems
+	}
+
+	if len(l.lines)-1 < header.commentLines {
+		l.report(len(l.lines)+1, "header declares %d comment lines, file has %d lines after the header", header.commentLines, len(l.lines)-1)
+		return l.problems
+	}
+	l.next = 1 + header.commentLines
+
+	required := make([]rune, 0, 95+len(deutschChars))
+	for c := rune(32); c <= 126; c++ {
+		required = append(required, c)
+	}
+	required = append(required, deutschChars...)
+
+	for i, code := range required {
+		if l.next >= len(l.lines) {
+			for _, missing := range required[i:] {
+				l.report(len(l.lines)+1, "missing required character %s", describeCode(missing))
+			}
+			return l.problems
+		}
+		l.glyph(code)
+	}
+
+	seen := make(map[rune]int)
+	tagged := 0
+	for l.next < len(l.lines) {
+		line := l.next + 1
+		tag := strings.TrimSpace(l.lines[l.next])
+		l.next++
+		if tag == "" {
+			continue
+		}
+		code, _, ok := parseCodeTag(tag)
+		if !ok {
+			l.report(line, "invalid code tag %q", tag)
+			continue
+		}
+		// Redefining a required character is common practice (fonts that
+		// include all of Latin-1 tag Ä-ß again), so only repeated tags count.
+		if first, dup := seen[code]; code == -1 {
+			l.report(line, "code tag -1 is reserved and will be ignored")
+		} else if dup {
+			l.report(line, "duplicate code tag %s, first defined on line %d", describeCode(code), first)
+		} else {
+			seen[code] = line
+		}
+		tagged++
+		if l.next >= len(l.lines) {
+			l.report(line, "code tag %s has no character data", describeCode(code))
+			break
+		}
+		l.glyph(code)
+	}
+
+	if header.fields > 8 && header.codeTag != tagged {
+		l.report(1, "header declares %d code-tagged characters, found %d", header.codeTag, tagged)
+	}
+	return l.problems
+}
+
+// header checks the first line and reports whether the rest of the file can
+// be checked against it.
+func (l *linter) header() (Metadata, bool) {
+	line := l.lines[0]
+	if !strings.HasPrefix(line, "flf2a") && !strings.HasPrefix(line, "tlf2a") {
+		l.report(1, "header must start with flf2a, got %q", truncate(line, 10))
+		return Metadata{}, false
+	}
+
+	// TOIlet fonts may use a space as the hardblank; in a FIGfont it means
+	// the hardblank is missing.
+	hb, size := utf8.DecodeRuneInString(line[5:])
+	if size == 0 || (hb == ' ' || hb == '\t') && strings.HasPrefix(line, "flf2a") {
+		l.report(1, "header is missing the hardblank character after the signature")
+		return Metadata{}, false
+	}
+	l.hardblank = hb
+
+	fields := strings.Fields(line[5+size:])
+	names := []string{"height", "baseline", "max_length", "old_layout", "comment_lines", "print_direction", "full_layout", "codetag_count"}
+	if len(fields) < 5 {
+		l.report(1, "header has %d numeric fields, need at least 5 (height baseline max_length old_layout comment_lines)", len(fields))
+		return Metadata{}, false
+	}
+	if len(fields) > len(names) {
+		l.report(1, "header has %d numeric fields, expected at most %d", len(fields), len(names))
+	}
+
+	values := make([]int, len(names))
+	for i, f := range fields[:min(len(fields), len(names))] {
+		v, err := strconv.Atoi(f)
+		if err != nil {
+			l.report(1, "header field %s is not a number: %q", names[i], f)
+			if i < 5 {
+				return Metadata{}, false
+			}
+			continue
+		}
+		values[i] = v
+	}
+
+	meta := Metadata{
+		hardBlank:      hb,
+		height:         values[0],
+		baseline:       values[1],
+		maxLength:      values[2],
+		oldLayout:      values[3],
+		commentLines:   values[4],
+		printDirection: values[5],
+		fullLayout:     values[6],
+		codeTag:        values[7],
+		fields:         len(fields) + 1,
+	}
+
+	ok := true
+	if meta.height < 1 {
+		l.report(1, "height must be at least 1, got %d", meta.height)
+		ok = false
+	}
+	if meta.baseline < 1 || meta.baseline > max(meta.height, 1) {
+		l.report(1, "baseline must be between 1 and the height %d, got %d", meta.height, meta.baseline)
+	}
+	if meta.maxLength < 1 {
+		l.report(1, "max_length must be at least 1, got %d", meta.maxLength)
+	}
+	if meta.oldLayout < -1 || meta.oldLayout > 63 {
+		l.report(1, "old_layout must be between -1 and 63, got %d", meta.oldLayout)
+	}
+	if meta.commentLines < 0 {
+		l.report(1, "comment_lines must not be negative, got %d", meta.commentLines)
+		ok = false
+	}
+	if len(fields) > 5 && meta.printDirection != 0 && meta.printDirection != 1 {
+		l.report(1, "print_direction must be 0 or 1, got %d", meta.printDirection)
+	}
+	if len(fields) > 6 && (meta.fullLayout < 0 || meta.fullLayout > 32767) {
+		l.report(1, "full_layout must be between 0 and 32767, got %d", meta.fullLayout)
+	}
+	if len(fields) > 7 && meta.codeTag < 0 {
+		l.report(1, "codetag_count must not be negative, got %d", meta.codeTag)
+	}
+
+	l.height, l.maxLength = meta.height, meta.maxLength
+	return meta, ok
+}
+
+// glyph checks the rows of one character starting at the next line. Like
+// Parse it reads exactly height rows, unless an earlier row with a double
+// endmark shows the character is short, in which case it reports the row
+// count and resynchronises there so later characters are still checked.
+func (l *linter) glyph(code rune) {
+	start := l.next
+	char := describeCode(code)
+	rows := min(l.height, len(l.lines)-start)
+	last := start + rows - 1
+
+	if rows < l.height {
+		l.report(last+1, "character %s has %d rows, expected %d", char, rows, l.height)
+	} else if !doubleEndmark(l.lines[last]) {
+		end := -1
+		for i := start; i < last; i++ {
+			if doubleEndmark(l.lines[i]) {
+				end = i
+				break
+			}
+		}
+		if end >= 0 {
+			rows, last = end-start+1, end
+			l.report(start+1, "character %s has %d rows, expected %d", char, rows, l.height)
+		} else {
+			l.report(last+1, "last row of character %s does not end with a double endmark", char)
+		}
+	}
+	l.next = start + rows
+
+	var endmark rune
+	width := -1
+	for i := start; i <= last; i++ {
+		line := strings.TrimRight(l.lines[i], " ")
+		if line != l.lines[i] {
+			l.report(i+1, "character %s has trailing spaces after the endmark", char)
+		}
+		if line == "" {
+			l.report(i+1, "character %s has an empty row", char)
+			continue
+		}
+
+		mark, _ := utf8.DecodeLastRuneInString(line)
+		if i == start {
+			endmark = mark
+			if mark == l.hardblank {
+				l.report(i+1, "character %s uses the hardblank %q as its endmark", char, mark)
+			}
+		} else if mark != endmark {
+			l.report(i+1, "character %s mixes endmarks %q and %q", char, endmark, mark)
+		}
+		if i < last && doubleEndmark(line) {
+			l.report(i+1, "row of character %s ends with a double endmark before its last row", char)
+		}
+
+		n := utf8.RuneCountInString(line)
+		if n > l.maxLength {
+			l.report(i+1, "row of character %s is %d wide, more than max_length %d", char, n, l.maxLength)
+		}
+
+		trim := 1
+		if i == last {
+			trim = 2
+		}
+		w := max(n-trim, 0)
+		if width >= 0 && w != width {
+			l.report(i+1, "row of character %s is %d wide, other rows are %d", char, w, width)
+		}
+		if width < 0 {
+			width = w
+		}
+	}
+}
+
+// doubleEndmark reports whether a row ends with the same character twice.
+func doubleEndmark(line string) bool {
+	line = strings.TrimRight(line, " ")
+	last, size := utf8.DecodeLastRuneInString(line)
+	if size == 0 {
+		return false
+	}
+	prev, _ := utf8.DecodeLastRuneInString(line[:len(line)-size])
+	return prev == last
+}
+
+// describeCode formats a character code for problem messages.
+func describeCode(code rune) string {
+	if code > ' ' && code < utf8.MaxRune && utf8.ValidRune(code) && code != 0x7f {
+		return fmt.Sprintf("%q (%d)", code, code)
+	}
+	return fmt.Sprintf("%d", code)
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
@@ -0,0 +1,112 @@
+package font
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/phantompunk/fig/internal/assert"
+)
+
+// lintFont builds a font with height-2 glyphs "ab@" / "cd@@" for every
+// required character, then lets edit adjust its lines before linting. Line n
+// of the file is lines[n-1]; the first glyph starts on line 3.
+func lintFont(header string, edit func(lines []string) []string) []string {
+	lines := []string{header, "a comment"}
+	for range 95 + 7 {
+		lines = append(lines, "ab@", "cd@@")
+	}
+	if edit != nil {
+		lines = edit(lines)
+	}
+	problems := Lint([]byte(strings.Join(lines, "\n") + "\n"))
+	out := make([]string, len(problems))
+	for i, p := range problems {
+		out[i] = p.String()
+	}
+	return out
+}
+
+func TestLint_CleanFont(t *testing.T) {
+	got := lintFont("flf2a$ 2 1 4 0 1 0 0 1", func(lines []string) []string {
+		return append(lines, "0x263A  SMILE", ":)@", ":(@@")
+	})
+	assert.Equal(t, len(got), 0)
+}
+
+func TestLint_Header(t *testing.T) {
+	got := lintFont("flf2a$ 2 3 4 99 1 2 40000 x", nil)
+	assert.Equal(t, strings.Join(got, "\n"), strings.Join([]string{
+		"1: header field codetag_count is not a number: \"x\"",
+		"1: baseline must be between 1 and the height 2, got 3",
+		"1: old_layout must be between -1 and 63, got 99",
+		"1: print_direction must be 0 or 1, got 2",
+		"1: full_layout must be between 0 and 32767, got 40000",
+	}, "\n"))
+
+	got = lintFont("flf2b$ 2 1 4 0 1", nil)
+	assert.Equal(t, got[0], "1: header must start with flf2a, got \"flf2b$ 2 1...\"")
+
+	got = lintFont("flf2a$ 2 1 4", nil)
+	assert.Equal(t, len(got), 1)
+}
+
+func TestLint_Glyphs(t *testing.T) {
+	got := lintFont("flf2a$ 2 1 4 0 1", func(lines []string) []string {
+		lines[2] = "ab#"    // line 3: ' ' mixes endmarks
+		lines[4] = "abcde@" // line 5: '!' wider than max length and other rows
+		lines[7] = "cd@"    // line 8: '"' last row without double endmark
+		lines[8] = "ab$"    // line 9: '#' uses the hardblank as endmark
+		lines[9] = "cd$$ "  // line 10: trailing spaces
+		lines[10] = "ab@@"  // line 11: '$' has only one row
+		return append(lines[:11], lines[12:]...)
+	})
+	assert.Equal(t, strings.Join(got, "\n"), strings.Join([]string{
+		"4: character 32 mixes endmarks '#' and '@'",
+		"5: row of character '!' (33) is 6 wide, more than max_length 4",
+		"6: row of character '!' (33) is 2 wide, other rows are 5",
+		"8: last row of character '\"' (34) does not end with a double endmark",
+		"8: row of character '\"' (34) is 1 wide, other rows are 2",
+		"9: character '#' (35) uses the hardblank '$' as its endmark",
+		"10: character '#' (35) has trailing spaces after the endmark",
+		"11: character '$' (36) has 1 rows, expected 2",
+	}, "\n"))
+}
+
+func TestLint_CodeTagsAndMissingCharacters(t *testing.T) {
+	got := lintFont("flf2a$ 2 1 4 0 1 0 0 5", func(lines []string) []string {
+		return append(lines,
+			"0x41 redefining A is fine", "ab@", "cd@@", // lines 207-209
+			"300", "ab@", "cd@@", // 210-212
+			"0454 again", "ab@", "cd@@", // 213-215: 0454 octal is 300
+			"-1", "ab@", "cd@@", // 216-218
+			"oops", // 219
+		)
+	})
+	assert.Equal(t, strings.Join(got, "\n"), strings.Join([]string{
+		"213: duplicate code tag 'Ĭ' (300), first defined on line 210",
+		"216: code tag -1 is reserved and will be ignored",
+		"219: invalid code tag \"oops\"",
+		"1: header declares 5 code-tagged characters, found 4",
+	}, "\n"))
+
+	got = lintFont("flf2a$ 2 1 4 0 1", func(lines []string) []string {
+		return lines[:len(lines)-4]
+	})
+	assert.Equal(t, strings.Join(got, "\n"), strings.Join([]string{
+		"203: missing required character 'ü' (252)",
+		"203: missing required character 'ß' (223)",
+	}, "\n"))
+}
+
+func TestLint_BundledFontsParse(t *testing.T) {
+	// Lint is stricter than Parse, but anything Parse rejects must be reported.
+	names, err := BundledLoader().List()
+	assert.NilError(t, err)
+	for _, name := range names {
+		data, _, err := BundledLoader().Load(name)
+		assert.NilError(t, err)
+		if _, err := Parse(data, name, "embedded"); err != nil && len(Lint(data)) == 0 {
+			t.Errorf("%s: Parse failed with %v but Lint found no problems", name, err)
+		}
+	}
+}
@@ -1,10 +1,17 @@
 package font
 
 import (
+	"archive/zip"
 	"bytes"
 	"embed"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/phantompunk/fig/assets"
@@ -18,23 +25,106 @@ const (
 	FormatTLF               // .tlf — Toilet (future)
 )
 
+// String returns the file extension of the format without the dot.
+func (f Format) String() string {
+	switch f {
+	case FormatTLF:
+		return "tlf"
+	default:
+		return "flf"
+	}
+}
+
+// MarshalText encodes the format by name so it reads naturally in JSON.
+func (f Format) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText decodes a format written by MarshalText.
+func (f *Format) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "flf":
+		*f = FormatFLF
+	case "tlf":
+		*f = FormatTLF
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedFormat, text)
+	}
+	return nil
+}
+
 // FontLoader abstracts the source of font data.
 // New sources (HTTP, zip bundle) can implement this interface.
 type FontLoader interface {
 	// Load returns the raw bytes and format of a font file by name.
-	// name has no extension — the loader resolves the extension.
+	// name has no extension — the loader resolves the extension. A font
+	// the loader does not have is reported with an error matching
+	// ErrFontNotFound, so the registry moves on to the next loader; any
+	// other error stops the search.
 	Load(name string) ([]byte, Format, error)
 
 	// List returns all font names available from this source, without extensions.
 	List() ([]string, error)
 }
 
+// limitedLoader is implemented by loaders whose fonts can grow past their
+// stored size as they are read, such as ZipLoader. The registry passes its
+// MaxSize so reading stops there.
+type limitedLoader interface {
+	loadLimited(name string, maxSize int) ([]byte, Format, error)
+}
+
+// ControlLoader is implemented by loaders that can also supply FIGlet
+// control (.flc) files, which figlet looks up alongside fonts.
+type ControlLoader interface {
+	// LoadControl returns the control file with the given name, without
+	// extension.
+	LoadControl(name string) ([]byte, error)
+}
+
+// EmbedLoader loads fonts embedded in the binary. When Index describes a
+// font, as the generated index of the bundled fonts does, the registry can
+// report its metadata without parsing it.
 type EmbedLoader struct {
-	FS  embed.FS
-	Dir string
+	FS    embed.FS
+	Dir   string
+	Index map[string]FontInfo
 }
 
+// String names the loader in font listings.
+func (e EmbedLoader) String() string { return "embedded" }
+
 func (e EmbedLoader) Load(name string) ([]byte, Format, error) {
+	return FSLoader{FS: e.FS, Dir: e.Dir, Name: e.String()}.Load(name)
+}
+
+// List returns all font names in the embedded directory, without extensions.
+func (e EmbedLoader) List() ([]string, error) {
+	return FSLoader{FS: e.FS, Dir: e.Dir, Name: e.String()}.List()
+}
+
+// Info returns the indexed description of the named font.
+func (e EmbedLoader) Info(name string) (FontInfo, bool) {
+	info, ok := e.Index[name]
+	return info, ok
+}
+
+func (e EmbedLoader) LoadControl(name string) ([]byte, error) {
+	return FSLoader{FS: e.FS, Dir: e.Dir, Name: e.String()}.LoadControl(name)
+}
+
+// FSLoader loads fonts from a directory of any fs.FS, such as an fstest.MapFS
+// or a mounted archive. Name describes the source in font listings.
+type FSLoader struct {
+	FS   fs.FS
+	Dir  string
+	Name string
+}
+
+// String names the loader in font listings.
+func (l FSLoader) String() string { return l.Name }
+
+func (l FSLoader) Load(name string) ([]byte, Format, error) {
 	candidates := []struct {
 		ext    string
 		format Format
@@ -44,22 +134,25 @@ func (e EmbedLoader) Load(name string) ([]byte, Format, error) {
 	}
 
 	for _, c := range candidates {
-		path := filepath.Join(e.Dir, name+c.ext)
-		data, err := e.FS.ReadFile(path)
+		data, err := fs.ReadFile(l.FS, path.Join(l.Dir, name+c.ext))
 		if err == nil {
 			return data, c.format, nil
 		}
+		// Names os.DirFS refuses, such as ones climbing out with "..",
+		// are not there either.
+		if !errors.Is(err, fs.ErrNotExist) && !errors.Is(err, fs.ErrInvalid) {
+			return nil, 0, err
+		}
 	}
 
-	return nil, 0, fmt.Errorf("font %q not found in embedded filesystem", name)
-
+	return nil, 0, &NotFoundError{Name: name, Loaders: []string{l.Name}}
 }
 
-// List returns all font names in the embedded directory, without extensions.
-func (e EmbedLoader) List() ([]string, error) {
-	entries, err := e.FS.ReadDir(e.Dir)
+// List returns all font names in the directory, without extensions.
+func (l FSLoader) List() ([]string, error) {
+	entries, err := fs.ReadDir(l.FS, l.Dir)
 	if err != nil {
-		return nil, fmt.Errorf("listing embedded fonts in %q: %w", e.Dir, err)
+		return nil, fmt.Errorf("listing fonts in %s: %w", l.Name, err)
 	}
 
 	names := make([]string, 0, len(entries))
@@ -69,32 +162,259 @@ func (e EmbedLoader) List() ([]string, error) {
 		}
 		name := entry.Name()
 		if isFontFile(name) {
-			names = append(names, strings.TrimSuffix(name, filepath.Ext(name)))
+			names = append(names, strings.TrimSuffix(name, path.Ext(name)))
 		}
 	}
 	return names, nil
 }
 
+func (l FSLoader) LoadControl(name string) ([]byte, error) {
+	data, err := fs.ReadFile(l.FS, path.Join(l.Dir, name+".flc"))
+	if err != nil {
+		return nil, fmt.Errorf("control file %q not found in %s", name, l.Name)
+	}
+	return data, nil
+}
+
+// DirLoader loads fonts from a directory on the local filesystem.
+type DirLoader struct {
+	Dir string
+}
+
+// String names the loader in font listings.
+func (d DirLoader) String() string { return d.Dir }
+
+func (d DirLoader) Load(name string) ([]byte, Format, error) {
+	return d.fs().Load(name)
+}
+
+// List returns all font names in the directory, without extensions.
+func (d DirLoader) List() ([]string, error) {
+	return d.fs().List()
+}
+
+func (d DirLoader) LoadControl(name string) ([]byte, error) {
+	return d.fs().LoadControl(name)
+}
+
+func (d DirLoader) fs() FSLoader {
+	return FSLoader{FS: os.DirFS(d.Dir), Dir: ".", Name: d.Dir}
+}
+
+// FileLoader loads fonts and control files named by a reference IsPath
+// treats as a file, such as "./brand/logo.flf", and has no others. Registries
+// only read files by path when given one, so programs that take font names
+// from untrusted input should leave it out.
+type FileLoader struct{}
+
+// String names the loader in font listings.
+func (FileLoader) String() string { return "file" }
+
+// Load reads the font file name refers to. Its format follows the extension.
+func (FileLoader) Load(name string) ([]byte, Format, error) {
+	data, err := readPath(name)
+	if err != nil {
+		return nil, 0, err
+	}
+	if strings.EqualFold(filepath.Ext(name), ".tlf") {
+		return data, FormatTLF, nil
+	}
+	return data, FormatFLF, nil
+}
+
+// List returns no names: files are only loaded when asked for by path.
+func (FileLoader) List() ([]string, error) {
+	return nil, nil
+}
+
+func (FileLoader) LoadControl(name string) ([]byte, error) {
+	return readPath(name)
+}
+
+// readPath reads the file ref names, reporting references that are not
+// paths, or name no file, as not found.
+func readPath(ref string) ([]byte, error) {
+	if !IsPath(ref) {
+		return nil, &NotFoundError{Name: ref}
+	}
+	data, err := os.ReadFile(strings.TrimPrefix(ref, "file://"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, &NotFoundError{Name: ref}
+	}
+	return data, err
+}
+
+// ZipLoader loads fonts from a zip archive bundling many fonts. Fonts may sit
+// anywhere in the archive; they are looked up by file name without extension,
+// and the first entry wins when several directories hold the same name.
+type ZipLoader struct {
+	name     string
+	fonts    map[string]*zip.File
+	controls map[string]*zip.File
+	order    []string
+}
+
+// OpenZip reads the font bundle at path into memory.
+func OpenZip(path string) (*ZipLoader, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewZipLoader(data, path)
+}
+
+// NewZipLoader returns a loader over the zip archive in data. name describes
+// the bundle in font listings.
+func NewZipLoader(data []byte, name string) (*ZipLoader, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("opening font bundle %s: %w", name, err)
+	}
+
+	z := &ZipLoader{name: name, fonts: make(map[string]*zip.File), controls: make(map[string]*zip.File)}
+	for _, file := range zr.File {
+		base := path.Base(file.Name)
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		if strings.EqualFold(path.Ext(base), ".flc") {
+			control := strings.TrimSuffix(base, path.Ext(base))
+			if _, ok := z.controls[control]; !ok {
+				z.controls[control] = file
+			}
+			continue
+		}
+		if !isFontFile(base) {
+			continue
+		}
+		font := strings.TrimSuffix(base, path.Ext(base))
+		if _, ok := z.fonts[font]; !ok {
+			z.fonts[font] = file
+			z.order = append(z.order, font)
+		}
+	}
+	return z, nil
+}
+
+// String names the loader in font listings.
+func (z *ZipLoader) String() string { return z.name }
+
+// Load returns the named font, decompressed. Fonts larger than
+// DefaultLimits.MaxSize once decompressed are rejected; registries apply
+// their own limit instead.
+func (z *ZipLoader) Load(name string) ([]byte, Format, error) {
+	return z.loadLimited(name, DefaultLimits.MaxSize)
+}
+
+func (z *ZipLoader) loadLimited(name string, maxSize int) ([]byte, Format, error) {
+	file, ok := z.fonts[name]
+	if !ok {
+		return nil, 0, &NotFoundError{Name: name, Loaders: []string{z.name}}
+	}
+
+	data, err := readZipFile(file, maxSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	format := FormatFLF
+	if strings.EqualFold(path.Ext(file.Name), ".tlf") {
+		format = FormatTLF
+	}
+	return data, format, nil
+}
+
+// List returns the names of all fonts in the archive, in archive order.
+func (z *ZipLoader) List() ([]string, error) {
+	return slices.Clone(z.order), nil
+}
+
+func (z *ZipLoader) LoadControl(name string) ([]byte, error) {
+	file, ok := z.controls[name]
+	if !ok {
+		return nil, fmt.Errorf("control file %q not found in %s", name, z.name)
+	}
+	return readZipFile(file, DefaultLimits.MaxSize)
+}
+
+// readZipFile decompresses file, returning a LimitError once it grows past
+// maxSize bytes unless maxSize is 0. It reads one byte past the limit rather
+// than trusting the size the archive declares, so a zip bomb is cut off
+// early.
+func readZipFile(file *zip.File, maxSize int) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	if maxSize <= 0 {
+		return io.ReadAll(rc)
+	}
+	data, err := io.ReadAll(io.LimitReader(rc, int64(maxSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if err := check("MaxSize", len(data), maxSize); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
 // isFontFile reports whether the filename has a recognised font extension.
 func isFontFile(name string) bool {
 	ext := strings.ToLower(filepath.Ext(name))
 	return ext == ".flf" || ext == ".tlf"
 }
 
-// ListFonts returns a list of available font names.
-func ListFonts() []string {
-	files, err := assets.FontFS.ReadDir(".")
-	if err != nil {
-		return nil
+// IsPath reports whether a font reference names a file rather than a font:
+// it contains a path separator, ends in a font or control file extension or
+// is a file:// URL. Other dots, as in "ascii.12", are part of a font name.
+func IsPath(ref string) bool {
+	if strings.HasPrefix(ref, "file://") ||
+		strings.ContainsRune(ref, '/') ||
+		strings.ContainsRune(ref, filepath.Separator) {
+		return true
 	}
+	return isFontFile(ref) || strings.EqualFold(filepath.Ext(ref), ".flc")
+}
+
+// LoadFile reads and parses the font file at path, which may also be given
+// as a file:// URL. The font is named after the file without its extension,
+// and parse errors report the path and line. Fonts are held to
+// DefaultLimits.
+func LoadFile(path string) (*Font, error) {
+	return loadFile(path, DefaultLimits)
+}
 
-	names := []string{}
-	for _, file := range files {
-		name := file.Name()[:len(file.Name())-4]
-		names = append(names, name)
+func loadFile(path string, limits Limits) (*Font, error) {
+	path = strings.TrimPrefix(path, "file://")
+	// Check the size before reading; zipped fonts are checked again once
+	// decompressed.
+	if info, err := os.Stat(path); err == nil {
+		if err := check("MaxSize", int(info.Size()), limits.MaxSize); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, &NotFoundError{Name: path}
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	return names
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	f, err := ParseLimited(data, name, path, limits)
+	if err != nil {
+		var perr *ParseError
+		if errors.As(err, &perr) {
+			perr.File = path
+		}
+		return nil, err
+	}
+	if strings.EqualFold(filepath.Ext(path), ".tlf") {
+		f.format = FormatTLF
+	}
+	return f, nil
 }
 
 func loadFont(name string) (*Font, error) {
@@ -105,10 +425,10 @@ func loadFont(name string) (*Font, error) {
 		return nil, err
 	}
 
-	return parseFont(name, bytes.NewReader(data))
+	return parseFont(name, bytes.NewReader(data), DefaultLimits)
 }
 
 // BundledLoader returns a FontLoader for the fonts embedded at build time.
 func BundledLoader() FontLoader {
-	return EmbedLoader{FS: assets.FontFS, Dir: "."}
+	return EmbedLoader{FS: assets.FontFS, Dir: ".", Index: bundledIndex()}
 }
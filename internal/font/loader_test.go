@@ -1 +1,184 @@
 package font
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/phantompunk/fig/internal/assert"
+)
+
+func TestDirLoader(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "mini.flf"), minimalFLF(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a font"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	loader := DirLoader{Dir: dir}
+
+	names, err := loader.List()
+	assert.NilError(t, err)
+	assert.Equal(t, len(names), 1)
+	assert.Equal(t, names[0], "mini")
+
+	data, format, err := loader.Load("mini")
+	assert.NilError(t, err)
+	assert.Equal(t, format, FormatFLF)
+	assert.Equal(t, string(data), string(minimalFLF()))
+
+	_, _, err = loader.Load("missing")
+	assert.True(t, err != nil)
+}
+
+func TestFSLoader(t *testing.T) {
+	loader := FSLoader{
+		FS: fstest.MapFS{
+			"fonts/mini.flf":   {Data: minimalFLF()},
+			"fonts/toilet.tlf": {Data: minimalFLF()},
+			"fonts/sub/x.flf":  {Data: minimalFLF()},
+			"other.flf":        {Data: minimalFLF()},
+		},
+		Dir:  "fonts",
+		Name: "test bundle",
+	}
+
+	names, err := loader.List()
+	assert.NilError(t, err)
+	assert.Equal(t, len(names), 2)
+	assert.Equal(t, names[0], "mini")
+	assert.Equal(t, names[1], "toilet")
+
+	_, format, err := loader.Load("toilet")
+	assert.NilError(t, err)
+	assert.Equal(t, format, FormatTLF)
+
+	_, _, err = loader.Load("other")
+	assert.True(t, err != nil)
+	assert.Equal(t, loader.String(), "test bundle")
+}
+
+func TestDirLoader_ShadowsBundledFonts(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "standard.flf"), minimalFLF(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	r := NewRegistry(DirLoader{Dir: dir}, BundledLoader())
+
+	f, err := r.Get("standard")
+	assert.NilError(t, err)
+	assert.Equal(t, f.Height(), 1)
+	assert.Equal(t, f.Source(), dir)
+
+	f, err = r.Get("slant")
+	assert.NilError(t, err)
+	assert.Equal(t, f.Source(), "embedded")
+}
+
+func TestIsPath(t *testing.T) {
+	assert.False(t, IsPath("standard"))
+	assert.False(t, IsPath("dos rebel"))
+	assert.False(t, IsPath("ascii.12"))
+	assert.True(t, IsPath("logo.flf"))
+	assert.True(t, IsPath("upper.FLC"))
+	assert.True(t, IsPath("./brand/logo"))
+	assert.True(t, IsPath("file:///fonts/logo.flf"))
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "logo.tlf")
+	if err := os.WriteFile(path, minimalFLF(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := NewRegistry(FileLoader{}).Get(path)
+	assert.NilError(t, err)
+	assert.Equal(t, f.Name(), "logo")
+	assert.Equal(t, f.Source(), path)
+	assert.Equal(t, f.Format(), FormatTLF)
+
+	// Without a FileLoader the registry never reads files by path.
+	_, err = NewRegistry(DirLoader{Dir: dir}).Get(path)
+	assert.True(t, errors.Is(err, ErrFontNotFound))
+	_, err = NewRegistry(DirLoader{Dir: dir}).Get("../" + filepath.Base(dir) + "/logo")
+	assert.True(t, errors.Is(err, ErrFontNotFound))
+
+	f, err = LoadFile("file://" + path)
+	assert.NilError(t, err)
+	assert.Equal(t, f.Name(), "logo")
+
+	bad := filepath.Join(dir, "bad.flf")
+	if err := os.WriteFile(bad, minimalFLF()[:40], 0644); err != nil {
+		t.Fatal(err)
+	}
+	_, err = LoadFile(bad)
+	var perr *ParseError
+	assert.True(t, errors.As(err, &perr))
+	assert.Equal(t, perr.File, bad)
+	assert.True(t, strings.HasPrefix(err.Error(), bad+":"))
+}
+
+// zipOf builds a zip archive holding the given files.
+func zipOf(t *testing.T, files map[string][]byte, order ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range order {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(files[name])
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParse_ZippedFont(t *testing.T) {
+	data := zipOf(t, map[string][]byte{"mini.flf": minimalFLF()}, "mini.flf")
+
+	f, err := Parse(data, "mini", "test")
+	assert.NilError(t, err)
+	assert.Equal(t, f.Height(), 1)
+
+	_, err = Parse(zipOf(t, nil), "empty", "test")
+	assert.True(t, err != nil)
+}
+
+func TestZipLoader(t *testing.T) {
+	files := map[string][]byte{
+		"fonts/mini.flf":   minimalFLF(),
+		"fonts/toilet.tlf": minimalFLF(),
+		"extra/mini.flf":   []byte("shadowed"),
+		"readme.txt":       []byte("not a font"),
+		"fonts/zipped.flf": zipOf(t, map[string][]byte{"zipped.flf": minimalFLF()}, "zipped.flf"),
+	}
+	data := zipOf(t, files, "fonts/mini.flf", "fonts/toilet.tlf", "extra/mini.flf", "readme.txt", "fonts/zipped.flf")
+	loader, err := NewZipLoader(data, "fonts.zip")
+	assert.NilError(t, err)
+
+	names, err := loader.List()
+	assert.NilError(t, err)
+	assert.Equal(t, strings.Join(names, ","), "mini,toilet,zipped")
+
+	r := NewRegistry(loader)
+	for _, name := range names {
+		f, err := r.Get(name)
+		assert.NilError(t, err)
+		assert.Equal(t, f.Source(), "fonts.zip")
+	}
+	f, _ := r.Get("toilet")
+	assert.Equal(t, f.Format(), FormatTLF)
+
+	_, err = NewZipLoader([]byte("not a zip"), "bad.zip")
+	assert.True(t, err != nil)
+}
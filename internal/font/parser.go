@@ -1,72 +1,261 @@
 package font
 
 import (
+	"archive/zip"
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
+// Parse parses FIGfont data. source describes where the data came from, such
+// as the loader or file path, and is reported by Font.Source. Like figlet,
+// Parse accepts a font compressed as a zip archive and reads its first file.
+// Fonts are held to DefaultLimits.
 func Parse(data []byte, name, source string) (*Font, error) {
-	return parseFont(name, bytes.NewReader(data))
+	return ParseLimited(data, name, source, DefaultLimits)
 }
 
-func parseFont(name string, data io.Reader) (*Font, error) {
-	scanner := bufio.NewScanner(data)
+// ParseLimited is like Parse but holds the font to limits, returning a
+// LimitError, on its own or inside a ParseError, for a font that exceeds
+// them.
+func ParseLimited(data []byte, name, source string, limits Limits) (*Font, error) {
+	data, err := unzipFont(data, limits.MaxSize)
+	if err != nil {
+		return nil, err
+	}
+	f, err := parseFont(name, bytes.NewReader(data), limits)
+	if err != nil {
+		return nil, err
+	}
+	f.source = source
+	if strings.HasPrefix(f.metadata.signature, "tlf2a") {
+		f.format = FormatTLF
+	}
+	return f, nil
+}
+
+// zipMagic starts every zip archive's first local file header.
+var zipMagic = []byte("PK\x03\x04")
+
+// unzipFont returns the first file of a zip archive, or data unchanged when
+// it is not one. Data, compressed or not, larger than maxSize bytes is
+// rejected with a LimitError unless maxSize is 0.
+func unzipFont(data []byte, maxSize int) ([]byte, error) {
+	if !bytes.HasPrefix(data, zipMagic) {
+		if err := check("MaxSize", len(data), maxSize); err != nil {
+			return nil, err
+		}
+		return data, nil
+	}
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("reading zipped font: %w", err)
+	}
+	for _, file := range zr.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		font, err := readZipFile(file, maxSize)
+		var lerr *LimitError
+		if err != nil && !errors.As(err, &lerr) {
+			return nil, fmt.Errorf("reading zipped font: %w", err)
+		}
+		return font, err
+	}
+	return nil, fmt.Errorf("reading zipped font: archive is empty")
+}
+
+// deutschChars are the seven German characters that follow the printable
+// ASCII glyphs in a FIGfont, in file order.
+var deutschChars = []rune{196, 214, 220, 228, 246, 252, 223}
+
+// ParseError reports a malformed font file and where the problem is. File is
+// empty unless the font was loaded from a path, and Char is the code of the
+// character whose glyph was being read, or 0 outside of a glyph.
+type ParseError struct {
+	File string
+	Line int
+	Char rune
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	if e.File == "" {
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	}
+	return fmt.Sprintf("%s:%d: %v", e.File, e.Line, e.Err)
+}
+
+func (e *ParseError) Unwrap() error { return e.Err }
+
+// lineScanner counts the lines it has scanned so errors can report them.
+type lineScanner struct {
+	*bufio.Scanner
+	line int
+}
+
+func (s *lineScanner) Scan() bool {
+	if s.Scanner.Scan() {
+		s.line++
+		return true
+	}
+	return false
+}
+
+// errorf returns a ParseError for the current line, or the line after it
+// when the scanner stopped at the end of the file.
+func (s *lineScanner) errorf(atEOF bool, format string, args ...any) *ParseError {
+	line := s.line
+	if atEOF {
+		line++
+	}
+	return &ParseError{Line: line, Err: fmt.Errorf(format, args...)}
+}
+
+func parseFont(name string, data io.Reader, limits Limits) (*Font, error) {
+	scanner := &lineScanner{Scanner: bufio.NewScanner(data)}
 	if !scanner.Scan() {
-		return nil, fmt.Errorf("empty font file")
+		return nil, scanner.errorf(true, "empty font file")
 	}
 
 	// flf2a$ 6 4 6 -1 4
 	header := scanner.Text()
 	meta, err := parseHeader(header)
+	if err == nil {
+		err = limits.header(meta)
+	}
 	if err != nil {
-		return nil, err
+		return nil, &ParseError{Line: 1, Err: err}
 	}
 
-	// skipCommentLines
+	comments := make([]string, 0, meta.commentLines)
 	for range meta.commentLines {
 		if !scanner.Scan() {
-			return nil, fmt.Errorf("unexpected eof in comments")
+			return nil, scanner.errorf(true, "unexpected eof in comments")
 		}
+		comments = append(comments, scanner.Text())
 	}
+	meta.comments = strings.Join(comments, "\n")
 
 	font := NewFigFont(name, meta)
+	add := func(code rune, char Glyph, line int) error {
+		if err := check("MaxGlyphs", len(font.glyphs)+1, limits.MaxGlyphs); err != nil {
+			return &ParseError{Line: line, Char: code, Err: err}
+		}
+		font.glyphs[code] = char
+		return nil
+	}
+
+	read := readCharacter
+	if strings.HasPrefix(meta.signature, "tlf2a") {
+		read = readTOIletCharacter
+	}
+
 	// parseCharacters
 	for charCode := 32; charCode <= 126; charCode++ {
-		char, err := readCharacter(scanner, meta.height)
+		char, err := read(scanner, meta.height)
 		if err != nil {
-			return nil, fmt.Errorf("failed to read character %d: %w", charCode, err)
+			perr := scanner.errorf(errors.Is(err, errGlyphEOF), "failed to read character %d: %w", charCode, err)
+			perr.Char = rune(charCode)
+			return nil, perr
+		}
+		if err := add(rune(charCode), char, scanner.line); err != nil {
+			return nil, err
 		}
-		font.glyphs[rune(charCode)] = char
+	}
+
+	// The German characters and code-tagged glyphs are optional in practice:
+	// like figlet, stop quietly at the end of the file or at the first
+	// section that does not parse.
+	for _, code := range deutschChars {
+		char, err := read(scanner, meta.height)
+		if err != nil {
+			return font, nil
+		}
+		if err := add(code, char, scanner.line); err != nil {
+			return nil, err
+		}
+		font.deutsch++
+	}
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		code, comment, ok := parseCodeTag(line)
+		if !ok {
+			break
+		}
+		tagLine := scanner.line
+		char, err := read(scanner, meta.height)
+		if err != nil {
+			break
+		}
+		// -1 is reserved by the spec and never rendered.
+		if code == -1 {
+			continue
+		}
+		if err := add(code, char, tagLine); err != nil {
+			return nil, err
+		}
+		font.tagged = append(font.tagged, CodeTag{Code: code, Comment: comment})
 	}
 
 	return font, nil
 }
 
+// parseCodeTag parses a code tag line such as "196  LATIN CAPITAL LETTER A
+// WITH DIAERESIS". The code may be decimal, octal (leading 0) or hexadecimal
+// (leading 0x) and may be negative.
+func parseCodeTag(line string) (rune, string, bool) {
+	field, comment, _ := strings.Cut(line, " ")
+	if tab := strings.IndexByte(field, '\t'); tab >= 0 {
+		field, comment = field[:tab], field[tab+1:]+" "+comment
+	}
+	code, err := strconv.ParseInt(field, 0, 32)
+	if err != nil {
+		return 0, "", false
+	}
+	return rune(code), strings.TrimSpace(comment), true
+}
+
 type headerParser struct {
-	fields []string
-	size   int
-	err    error
+	signature string
+	hardBlank rune
+	fields    []string
+	size      int
+	err       error
 }
 
 func newParser(header string) (*headerParser, error) {
 	if len(header) < 6 {
-		return nil, fmt.Errorf("invalid header: %q", header)
+		return nil, fmt.Errorf("invalid header %q: %w", header, ErrUnsupportedFormat)
 	}
 
-	if !strings.HasPrefix(header, "flf2a") {
-		return nil, fmt.Errorf("invalid header prefix: %q", header)
+	if !strings.HasPrefix(header, "flf2a") && !strings.HasPrefix(header, "tlf2a") {
+		return nil, fmt.Errorf("invalid header prefix %q: %w", header, ErrUnsupportedFormat)
 	}
 
-	fields := strings.Fields(header[5:])
+	// TOIlet fonts may use any UTF-8 character as the hardblank, even a
+	// space; a byte that is not valid UTF-8 is a Latin-1 hardblank from an
+	// older FIGfont.
+	hb, size := utf8.DecodeRuneInString(header[5:])
+	if hb == utf8.RuneError && size <= 1 {
+		hb, size = rune(header[5]), 1
+	}
+
+	fields := append([]string{header[5 : 5+size]}, strings.Fields(header[5+size:])...)
 	if len(fields) < 6 {
 		return nil, fmt.Errorf("invalid header format: expected at least 6 fields, got %d", len(fields))
 	}
 
-	return &headerParser{fields: fields, size: len(fields)}, nil
+	return &headerParser{signature: header[:5+size], hardBlank: hb, fields: fields, size: len(fields)}, nil
 }
 
 func (p *headerParser) parseInt(i int, name string) int {
@@ -110,6 +299,50 @@ const (
 	BitLayoutVSmushing = 1 << 14 // 16384
 )
 
+var layoutRuleNames = []string{
+	"equal character",
+	"underscore",
+	"hierarchy",
+	"opposite pair",
+	"big X",
+	"hardblank",
+	"horizontal fitting",
+	"horizontal smushing",
+	"vertical equal character",
+	"vertical underscore",
+	"vertical hierarchy",
+	"horizontal line",
+	"vertical line",
+	"vertical fitting",
+	"vertical smushing",
+}
+
+// FullLayoutRules names each bit set in a full_layout header value, lowest
+// bit first.
+func FullLayoutRules(mask int) []string {
+	var names []string
+	for bit, name := range layoutRuleNames {
+		if mask&(1<<bit) != 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// OldLayoutRules describes an old_layout header value: -1 is full width, 0
+// is horizontal fitting and a positive value is smushing with the rules in
+// its low six bits.
+func OldLayoutRules(mask int) []string {
+	switch {
+	case mask < 0:
+		return []string{"full width"}
+	case mask == 0:
+		return []string{"horizontal fitting"}
+	}
+	names := []string{"horizontal smushing"}
+	return append(names, FullLayoutRules(mask&(BitKern-1))...)
+}
+
 func parseSmushMode(mask int) SmushMode {
 	if mask < 0 {
 		return SmushMode{Enabled: false}
@@ -160,12 +393,20 @@ func parseHeader(header string) (Metadata, error) {
 		return Metadata{}, err
 	}
 
-	meta.hardBlank = rune(header[5])
+	meta.signature = parser.signature
+	meta.hardBlank = parser.hardBlank
 	meta.height = parser.parseInt(1, "height")
 	meta.baseline = parser.parseInt(2, "baseline")
 	meta.maxLength = parser.parseInt(3, "max_length")
 	meta.oldLayout = parser.parseInt(4, "old_layout")
 	meta.commentLines = parser.parseInt(5, "comment_lines")
+	meta.fields = min(parser.size, 9) // fields past codetag_count are ignored
+	if parser.err == nil && meta.height < 1 {
+		return Metadata{}, fmt.Errorf("invalid field height: must be at least 1, got %d", meta.height)
+	}
+	if parser.err == nil && meta.commentLines < 0 {
+		return Metadata{}, fmt.Errorf("invalid field comment_lines: must not be negative, got %d", meta.commentLines)
+	}
 
 	if parser.size > 6 {
 		meta.printDirection = parser.parseInt(6, "print_direction")
@@ -196,27 +437,50 @@ func parseHeader(header string) (Metadata, error) {
 	return meta, parser.err
 }
 
-func readCharacter(scanner *bufio.Scanner, height int) (Glyph, error) {
+// errGlyphEOF is returned by readCharacter when the file ends mid-glyph.
+var errGlyphEOF = errors.New("unexpected end of file while reading characters")
+
+// glyphScanner is the part of bufio.Scanner that readCharacter uses.
+type glyphScanner interface {
+	Scan() bool
+	Text() string
+}
+
+func readCharacter(scanner glyphScanner, height int) (Glyph, error) {
+	return readGlyph(scanner, height, false)
+}
+
+// readTOIletCharacter reads a glyph of a TOIlet font. TOIlet reads rows as
+// figlet does rather than as the spec says: it drops trailing whitespace and
+// then every trailing copy of the endmark, so fonts such as circle.tlf end
+// even their last row with a single endmark.
+func readTOIletCharacter(scanner glyphScanner, height int) (Glyph, error) {
+	return readGlyph(scanner, height, true)
+}
+
+// readGlyph reads height rows and trims their endmarks: exactly one, or two
+// on the last row, unless lenient.
+func readGlyph(scanner glyphScanner, height int, lenient bool) (Glyph, error) {
 	lines := make([]string, height)
 	width := 0
 
 	for i := range height {
 		if !scanner.Scan() {
-			return Glyph{}, fmt.Errorf("unexpected end of file while reading characters")
+			return Glyph{}, errGlyphEOF
 		}
 
-		line := scanner.Text()
-
 		trim := 1
 		if i == height-1 {
 			trim = 2
 		}
 
-		if len(line) < trim {
+		line, ok := trimEndmarks(scanner.Text(), trim)
+		if lenient {
+			line, ok = trimAllEndmarks(scanner.Text())
+		}
+		if !ok {
 			return Glyph{}, fmt.Errorf("glyph line is too short")
 		}
-
-		line = line[:len(line)-trim]
 		width = max(width, len(line))
 		lines[i] = line
 	}
@@ -226,3 +490,27 @@ func readCharacter(scanner *bufio.Scanner, height int) (Glyph, error) {
 		width: width,
 	}, nil
 }
+
+// trimEndmarks drops the last n runes of line, whole runes at a time so
+// multibyte endmarks, common in TOIlet fonts, are removed cleanly.
+func trimEndmarks(line string, n int) (string, bool) {
+	for range n {
+		_, size := utf8.DecodeLastRuneInString(line)
+		if size == 0 {
+			return "", false
+		}
+		line = line[:len(line)-size]
+	}
+	return line, true
+}
+
+// trimAllEndmarks drops trailing whitespace and then every copy of the
+// rune that ends what is left.
+func trimAllEndmarks(line string) (string, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	endmark, size := utf8.DecodeLastRuneInString(line)
+	if size == 0 {
+		return "", false
+	}
+	return strings.TrimRight(line, string(endmark)), true
+}
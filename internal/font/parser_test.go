@@ -159,3 +159,92 @@ func TestGlyphHardBlank(t *testing.T) {
 	assert.Equal(t, expected, strings.Join(g.lines, "\n"))
 	assert.Nil(t, err)
 }
+
+func TestReadTOIletCharacter(t *testing.T) {
+	// TOIlet rows lose trailing whitespace and every copy of the endmark,
+	// so a single endmark ends the last row too.
+	input := "(_)●  \n (o)●●●\n(_)●"
+	scanner := bufio.NewScanner(strings.NewReader(input))
+	g, err := readTOIletCharacter(scanner, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, "(_)\n (o)\n(_)", strings.Join(g.lines, "\n"))
+	assert.Equal(t, 4, g.width)
+
+	// FIGfont rows lose exactly the endmarks the spec calls for.
+	scanner = bufio.NewScanner(strings.NewReader("(_)@  \n(_)@@@"))
+	g, err = readCharacter(scanner, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, "(_)@ \n(_)@", strings.Join(g.lines, "\n"))
+
+	scanner = bufio.NewScanner(strings.NewReader(" \n"))
+	_, err = readTOIletCharacter(scanner, 1)
+	assert.ErrorContains(t, err, "glyph line is too short")
+}
+
+func TestParseCommentsAndExtendedGlyphs(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("flf2a$ 1 1 2 -1 2 0 0 2\n")
+	b.WriteString("first comment\nsecond comment\n")
+	for range 95 + 7 {
+		b.WriteString(" @@\n")
+	}
+	b.WriteString("0x263A  WHITE SMILING FACE\n:@@\n")
+	b.WriteString("-1 reserved\nx@@\n")
+	b.WriteString("0240\nY@@\n")
+
+	f, err := Parse([]byte(b.String()), "tagged", "test")
+	assert.Nil(t, err)
+	assert.Equal(t, "first comment\nsecond comment", f.Comments())
+	assert.Equal(t, 7, f.DeutschCount())
+	assert.Equal(t, 2, f.CodeTagCount())
+	assert.Equal(t, []CodeTag{{Code: 0x263A, Comment: "WHITE SMILING FACE"}, {Code: 0240}}, f.CodeTags())
+	assert.Equal(t, []string{":"}, f.getGlyph(0x263A).lines)
+	assert.NotContains(t, f.Runes(), rune(-1))
+	assert.Len(t, f.Runes(), 95+7+2)
+}
+
+func TestParseStopsAtMissingGermanGlyphs(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("flf2a$ 1 1 2 0 0\n")
+	for range 95 + 3 {
+		b.WriteString(" @@\n")
+	}
+
+	f, err := Parse([]byte(b.String()), "partial", "test")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, f.DeutschCount())
+	assert.False(t, f.HasFullLayout())
+	assert.Empty(t, f.CodeTags())
+}
+
+func TestLayoutRules(t *testing.T) {
+	assert.Equal(t, []string{"full width"}, OldLayoutRules(-1))
+	assert.Equal(t, []string{"horizontal fitting"}, OldLayoutRules(0))
+	assert.Equal(t, []string{"horizontal smushing", "underscore", "hierarchy"}, OldLayoutRules(6))
+	assert.Equal(t, []string{"equal character", "horizontal smushing", "vertical fitting"}, FullLayoutRules(1|128|8192))
+	assert.Nil(t, FullLayoutRules(0))
+}
+
+func TestParseErrorLine(t *testing.T) {
+	_, err := Parse([]byte("flf2a$ 1 1 2 0 0 x\n"), "bad", "test")
+	var perr *ParseError
+	assert.ErrorAs(t, err, &perr)
+	assert.Equal(t, 1, perr.Line)
+
+	var b strings.Builder
+	b.WriteString("flf2a$ 1 1 2 0 1\ncomment\n")
+	for range 10 {
+		b.WriteString(" @@\n")
+	}
+	_, err = Parse([]byte(b.String()), "short", "test")
+	assert.ErrorAs(t, err, &perr)
+	assert.Equal(t, 13, perr.Line)
+	assert.ErrorIs(t, err, errGlyphEOF)
+	assert.Equal(t, "line 13: failed to read character 42: unexpected end of file while reading characters", err.Error())
+
+	b.WriteString("@\n")
+	_, err = Parse([]byte(b.String()), "short", "test")
+	assert.ErrorAs(t, err, &perr)
+	assert.Equal(t, 13, perr.Line)
+	assert.Contains(t, err.Error(), "glyph line is too short")
+}
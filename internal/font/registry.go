@@ -1,9 +1,10 @@
 package font
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
-
 )
 
 // entry is the value stored in the registry cache.
@@ -18,13 +19,110 @@ type entry struct {
 // Fonts are loaded and parsed once then reused across all callers.
 type FontRegistry struct {
 	loaders []FontLoader
-	cache   sync.Map // map[string]*entry
+	limits  Limits
+	aliases map[string]string // alias to canonical name
+	cache   sync.Map          // map[string]*entry
 }
 
 // NewRegistry returns a FontRegistry that searches the given loaders in order.
-// The first loader that successfully returns data for a name wins.
+// The first loader that successfully returns data for a name wins. Fonts are
+// held to DefaultLimits.
 func NewRegistry(loaders ...FontLoader) *FontRegistry {
-	return &FontRegistry{loaders: loaders}
+	return &FontRegistry{loaders: loaders, limits: DefaultLimits}
+}
+
+// SetLimits sets the limits fonts are held to when they are loaded, such as
+// tighter ones for fonts uploaded by users. Fonts already loaded are not
+// checked again, so call it before the first Get.
+func (r *FontRegistry) SetLimits(limits Limits) {
+	r.limits = limits
+}
+
+// SetAliases makes each alias in aliases load the font named by the
+// canonical name it maps to, even when a loader has a font of the alias's
+// own name. Aliases of aliases are ignored. Like SetLimits, call it before
+// the first Get.
+func (r *FontRegistry) SetAliases(aliases map[string]string) {
+	r.aliases = aliases
+}
+
+// Canonical returns the name the font called name is loaded as: its
+// canonical name if it is an alias, or else name itself.
+func (r *FontRegistry) Canonical(name string) string {
+	if c, ok := r.aliases[name]; ok {
+		if _, chained := r.aliases[c]; !chained {
+			return c
+		}
+	}
+	return name
+}
+
+// Info describes the named font. A font that is not loaded yet is described
+// from the index of the loader that has it, when that loader is an
+// IndexedLoader, so listing many fonts does not parse them all; otherwise,
+// or when the indexed font exceeds the registry's limits, the font is
+// loaded as by Get, and errors as Get would.
+func (r *FontRegistry) Info(name string) (FontInfo, error) {
+	name = r.Canonical(name)
+	if _, loaded := r.cache.Load(name); !loaded {
+		for _, l := range r.loaders {
+			if _, ok := l.(FileLoader); ok && IsPath(name) {
+				break
+			}
+			if il, ok := l.(IndexedLoader); ok {
+				info, ok := il.Info(name)
+				if ok && r.limits.info(info) != nil {
+					// Report the font as Get would: over its limits.
+					break
+				}
+				if ok {
+					info.Source = LoaderName(l)
+					return info, nil
+				}
+			}
+			data, format, err := r.loadFrom(l, name)
+			if errors.Is(err, ErrFontNotFound) {
+				continue
+			}
+			// l has the font: parse the data it returned into the cache,
+			// so Get does not read it again.
+			f, err := r.cached(name, func() (*Font, error) {
+				if err != nil {
+					return nil, err
+				}
+				return r.parse(l, name, data, format)
+			})
+			if err != nil {
+				return FontInfo{}, err
+			}
+			return f.Info(), nil
+		}
+	}
+	f, err := r.Get(name)
+	if err != nil {
+		return FontInfo{}, err
+	}
+	return f.Info(), nil
+}
+
+// Duplicates loads every available font and returns the pairs that render
+// alike, as FindDuplicates does. Aliases and fonts that fail to load are
+// skipped.
+func (r *FontRegistry) Duplicates(threshold float64) ([]Duplicate, error) {
+	names, err := r.Available()
+	if err != nil {
+		return nil, err
+	}
+	fonts := make([]*Font, 0, len(names))
+	for _, name := range names {
+		if r.Canonical(name) != name {
+			continue
+		}
+		if f, err := r.Get(name); err == nil {
+			fonts = append(fonts, f)
+		}
+	}
+	return FindDuplicates(fonts, threshold), nil
 }
 
 // Get returns the named font, loading and parsing it on first access.
@@ -32,10 +130,18 @@ func NewRegistry(loaders ...FontLoader) *FontRegistry {
 // Safe for concurrent use: if multiple goroutines request the same uncached
 // font simultaneously the file is loaded and parsed exactly once.
 func (r *FontRegistry) Get(name string) (*Font, error) {
+	return r.cached(name, func() (*Font, error) {
+		return r.load(name)
+	})
+}
+
+// cached returns the cache entry for name, filling it with load if it is
+// new.
+func (r *FontRegistry) cached(name string, load func() (*Font, error)) (*Font, error) {
 	actual, _ := r.cache.LoadOrStore(name, &entry{})
 	e := actual.(*entry)
 	e.once.Do(func() {
-		e.font, e.err = r.load(name)
+		e.font, e.err = load()
 	})
 	return e.font, e.err
 }
@@ -51,7 +157,7 @@ func (r *FontRegistry) Preload(names []string) error {
 		}
 	}
 	if len(errs) > 0 {
-		return fmt.Errorf("preload: %d font(s) failed: %v", len(errs), errs)
+		return fmt.Errorf("preload: %d font(s) failed: %w", len(errs), errors.Join(errs...))
 	}
 	return nil
 }
@@ -85,13 +191,88 @@ func (r *FontRegistry) Available() ([]string, error) {
 // load performs the actual file read and parse for a single font name.
 // It is called at most once per name (guarded by sync.Once in the entry).
 func (r *FontRegistry) load(name string) (*Font, error) {
+	if c := r.Canonical(name); c != name {
+		return r.Get(c)
+	}
+	searched := make([]string, 0, len(r.loaders))
 	for _, l := range r.loaders {
-		data, _, err := l.Load(name)
+		if _, ok := l.(FileLoader); ok {
+			// Name the font after its file, and report parse errors
+			// against the path, as LoadFile does. Other names are no
+			// concern of a FileLoader, so it is not listed as searched.
+			if IsPath(name) {
+				return loadFile(name, r.limits)
+			}
+			continue
+		}
+		data, format, err := r.loadFrom(l, name)
+		if errors.Is(err, ErrFontNotFound) {
+			searched = append(searched, LoaderName(l))
+			continue
+		}
 		if err != nil {
+			return nil, err
+		}
+		return r.parse(l, name, data, format)
+	}
+	// Take "dos-rebel" for "dos rebel", or suggest names close to a typo.
+	names, _ := r.Available()
+	if match, ok := ResolveName(name, names); ok && match != name {
+		return r.Get(match)
+	}
+	return nil, &NotFoundError{Name: name, Loaders: searched, Suggestions: Suggest(name, names, 3)}
+}
+
+// parse parses a font's data as returned by l, held to the registry's
+// limits.
+func (r *FontRegistry) parse(l FontLoader, name string, data []byte, format Format) (*Font, error) {
+	f, err := ParseLimited(data, name, LoaderName(l), r.limits)
+	if err != nil {
+		return nil, err
+	}
+	f.format = format
+	return f, nil
+}
+
+// loadFrom returns the named font's data from l, held to the registry's
+// MaxSize while it is read when l is a limitedLoader.
+func (r *FontRegistry) loadFrom(l FontLoader, name string) ([]byte, Format, error) {
+	if ll, ok := l.(limitedLoader); ok {
+		return ll.loadLimited(name, r.limits.MaxSize)
+	}
+	return l.Load(name)
+}
+
+// Control returns the named FIGlet control file from the first loader
+// implementing ControlLoader that has it.
+func (r *FontRegistry) Control(name string) (*Control, error) {
+	for _, l := range r.loaders {
+		cl, ok := l.(ControlLoader)
+		if !ok {
 			continue
 		}
-		return Parse(data, name, fmt.Sprintf("loader:%T", l))
+		data, err := cl.LoadControl(name)
+		if err != nil {
+			continue
+		}
+		c, err := ParseControl(data, name)
+		if perr, ok := err.(*ParseError); ok {
+			perr.File = name + ".flc"
+			if _, ok := l.(FileLoader); ok {
+				perr.File = strings.TrimPrefix(name, "file://")
+			}
+		}
+		return c, err
 	}
-	return nil, fmt.Errorf("font %q not found in any loader", name)
+	return nil, fmt.Errorf("control file %q not found in any loader", name)
 }
 
+// LoaderName describes a loader for Font.Source and in NotFoundError.
+// Loaders can implement fmt.Stringer to provide a readable name; otherwise
+// the type name is used.
+func LoaderName(l FontLoader) string {
+	if s, ok := l.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%T", l)
+}
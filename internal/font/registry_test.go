@@ -29,7 +29,7 @@ func (s *stubLoader) Load(name string) ([]byte, Format, error) {
 
 	data, ok := s.fonts[name]
 	if !ok {
-		return nil, FormatFLF, fmt.Errorf("font %q not found", name)
+		return nil, FormatFLF, &NotFoundError{Name: name}
 	}
 	return data, FormatFLF, nil
 }
@@ -288,3 +288,28 @@ func TestAvailable_AllLoadersFail(t *testing.T) {
 		t.Error("expected error when all loaders fail, got nil")
 	}
 }
+
+// namedLoader wraps a stubLoader with a display name.
+type namedLoader struct {
+	*stubLoader
+	name string
+}
+
+func (n namedLoader) String() string { return n.name }
+
+func TestGet_RecordsSourceAndFormat(t *testing.T) {
+	r := NewRegistry(namedLoader{newStubLoader(map[string][]byte{"mini": minimalFLF()}), "in-memory"})
+
+	f, err := r.Get("mini")
+	assert.NilError(t, err)
+	assert.Equal(t, f.Source(), "in-memory")
+	assert.Equal(t, f.Format(), FormatFLF)
+}
+
+func TestGet_SourceFallsBackToLoaderType(t *testing.T) {
+	r := NewRegistry(newStubLoader(map[string][]byte{"mini": minimalFLF()}))
+
+	f, err := r.Get("mini")
+	assert.NilError(t, err)
+	assert.Equal(t, f.Source(), "*font.stubLoader")
+}
@@ -0,0 +1,65 @@
+package font
+
+import (
+	"slices"
+	"strings"
+)
+
+// Subset returns a copy of f that keeps only the glyphs for chars. The
+// space and the other required FIGfont characters that are not kept become
+// blank glyphs so the result is still a valid font, and code-tagged glyphs
+// outside chars are dropped along with their tags. A note listing the kept
+// characters is added to the comments.
+func (f *Font) Subset(chars []rune) *Font {
+	keep := make(map[rune]bool, len(chars)+1)
+	keep[' '] = true
+	for _, c := range chars {
+		keep[c] = true
+	}
+
+	meta := f.metadata
+	var tagged []CodeTag
+	for _, tag := range f.tagged {
+		if keep[tag.Code] {
+			tagged = append(tagged, tag)
+		}
+	}
+	if meta.fields > 8 {
+		meta.codeTag = len(tagged)
+	}
+
+	kept := make([]rune, 0, len(keep))
+	for c := range keep {
+		if _, ok := f.glyphs[c]; ok {
+			kept = append(kept, c)
+		}
+	}
+	slices.Sort(kept)
+	note := "Subset to " + strings.TrimSpace(string(kept))
+	if meta.commentLines > 0 {
+		meta.comments += "\n" + note
+	} else {
+		meta.comments = note
+	}
+	meta.commentLines++
+
+	sub := NewFigFont(f.name, meta)
+	sub.source = f.source
+	sub.format = f.format
+	sub.deutsch = f.deutsch
+	sub.tagged = tagged
+
+	blank := Glyph{lines: make([]string, meta.height)}
+	for c := rune(32); c <= 126; c++ {
+		sub.glyphs[c] = blank
+	}
+	for _, c := range deutschChars[:f.deutsch] {
+		sub.glyphs[c] = blank
+	}
+	for c := range keep {
+		if g, ok := f.glyphs[c]; ok {
+			sub.glyphs[c] = g
+		}
+	}
+	return sub
+}
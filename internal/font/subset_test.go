@@ -0,0 +1,43 @@
+package font
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/phantompunk/fig/internal/assert"
+)
+
+func TestFont_Subset(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("flf2a$ 1 1 4 -1 1 0 0 2\nby someone\n")
+	for c := rune(32); c <= 126; c++ {
+		b.WriteString(string(c) + "@@\n")
+	}
+	for range 7 {
+		b.WriteString("?@@\n")
+	}
+	b.WriteString("0x263A  WHITE SMILING FACE\n:)@@\n300\nx@@\n")
+	f := Must(Parse([]byte(b.String()), "tagged", "test"))
+
+	sub := f.Subset([]rune("12:☺"))
+	assert.Equal(t, sub.Comments(), "by someone\nSubset to 12:☺")
+	assert.Equal(t, sub.CodeTagCount(), 1)
+	assert.Equal(t, len(sub.CodeTags()), 1)
+	assert.Equal(t, sub.DeutschCount(), 7)
+	assert.Equal(t, sub.glyphs['1'].lines[0], "1")
+	assert.Equal(t, sub.glyphs[' '].lines[0], " ")
+	assert.Equal(t, sub.glyphs['A'].lines[0], "")
+	assert.Equal(t, sub.glyphs[0x263A].lines[0], ":)")
+	_, ok := sub.glyphs[300]
+	assert.False(t, ok)
+
+	var buf bytes.Buffer
+	assert.NilError(t, Write(&buf, sub))
+	assert.Equal(t, len(Lint(buf.Bytes())), 0)
+	got, err := Parse(buf.Bytes(), "tagged", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameFont(t, got, sub)
+}
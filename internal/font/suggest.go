@@ -0,0 +1,100 @@
+package font
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// normalizeName folds case and drops spaces, dashes and underscores, so
+// "dos rebel", "dos-rebel" and "DOS_Rebel" compare equal.
+func normalizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' || r == '_' {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, name)
+}
+
+// ResolveName returns the one name in names that name refers to once case,
+// spaces, dashes and underscores are ignored. When several names match, as
+// "3-d" and "3d" do, only one that differs from name in case alone is taken.
+func ResolveName(name string, names []string) (string, bool) {
+	if slices.Contains(names, name) {
+		return name, true
+	}
+	var matches []string
+	for _, n := range names {
+		if normalizeName(n) == normalizeName(name) {
+			matches = append(matches, n)
+		}
+	}
+	if len(matches) == 1 {
+		return matches[0], true
+	}
+	for _, n := range matches {
+		if strings.EqualFold(n, name) {
+			return n, true
+		}
+	}
+	return "", false
+}
+
+// Suggest returns up to n of names that name is likely a typo of, closest
+// first. Names are compared normalized like ResolveName, and a name is only
+// suggested within about one edit for every three characters.
+func Suggest(name string, names []string, n int) []string {
+	type candidate struct {
+		name string
+		dist int
+	}
+	target := normalizeName(name)
+	limit := (len([]rune(target)) + 2) / 3
+
+	var found []candidate
+	for _, c := range names {
+		if d := editDistance(target, normalizeName(c)); d <= limit {
+			found = append(found, candidate{c, d})
+		}
+	}
+	slices.SortFunc(found, func(a, b candidate) int {
+		return cmp.Or(cmp.Compare(a.dist, b.dist), cmp.Compare(a.name, b.name))
+	})
+
+	suggestions := make([]string, 0, min(n, len(found)))
+	for _, c := range found[:min(n, len(found))] {
+		suggestions = append(suggestions, c.name)
+	}
+	return suggestions
+}
+
+// editDistance returns the number of single-rune insertions, deletions,
+// substitutions and swaps of neighbours that turn a into b.
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	// prev2, prev and cur are the rows of the distance matrix for s[:i-2],
+	// s[:i-1] and s[:i].
+	prev2 := make([]int, len(t)+1)
+	prev := make([]int, len(t)+1)
+	cur := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		cur[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(t)]
+}
@@ -0,0 +1,67 @@
+package font
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/phantompunk/fig/internal/assert"
+)
+
+var suggestNames = []string{"3-d", "3d", "dos rebel", "slant", "small slant", "smslant", "standard"}
+
+func TestResolveName(t *testing.T) {
+	tests := []struct {
+		name, want string
+		ok         bool
+	}{
+		{"slant", "slant", true},
+		{"dos-rebel", "dos rebel", true},
+		{"dos_rebel", "dos rebel", true},
+		{"DOS Rebel", "dos rebel", true},
+		{"Small-Slant", "small slant", true},
+		{"3D", "3d", true},
+		{"3_D", "", false}, // both 3-d and 3d
+		{"slnat", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := ResolveName(tt.name, suggestNames)
+		assert.Equal(t, got, tt.want)
+		assert.Equal(t, ok, tt.ok)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	suggest := func(name string, n int) string {
+		return strings.Join(Suggest(name, suggestNames, n), ", ")
+	}
+	assert.Equal(t, suggest("slnat", 3), "slant")
+	assert.Equal(t, suggest("smal-slant", 3), "small slant, smslant")
+	assert.Equal(t, suggest("standrd", 1), "standard")
+	assert.Equal(t, suggest("xqzw", 3), "")
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, editDistance("", "abc"), 3)
+	assert.Equal(t, editDistance("slant", "slant"), 0)
+	assert.Equal(t, editDistance("slnat", "slant"), 1)
+	assert.Equal(t, editDistance("kitten", "sitting"), 3)
+}
+
+func TestGet_ResolvesAndSuggests(t *testing.T) {
+	r := NewRegistry(newStubLoader(map[string][]byte{"dos rebel": minimalFLF()}))
+
+	f, err := r.Get("dos-rebel")
+	assert.NilError(t, err)
+	assert.Equal(t, f.Name(), "dos rebel")
+	g, _ := r.Get("dos rebel")
+	assert.True(t, f == g)
+
+	_, err = r.Get("dos rebl")
+	var nerr *NotFoundError
+	if !errors.As(err, &nerr) {
+		t.Fatalf("got %v, want a NotFoundError", err)
+	}
+	assert.Equal(t, strings.Join(nerr.Suggestions, ", "), "dos rebel")
+	assert.Equal(t, err.Error(), `font "dos rebl" not found in *font.stubLoader; did you mean "dos rebel"?`)
+}
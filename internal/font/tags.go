@@ -7,8 +7,9 @@ import (
 )
 
 type fontEntry struct {
-	Name string   `yaml:"name"`
-	Tags []string `yaml:"tags"`
+	Name    string   `yaml:"name"`
+	Tags    []string `yaml:"tags"`
+	Aliases []string `yaml:"aliases"`
 }
 
 type fontsYAML struct {
@@ -31,6 +32,22 @@ func LoadTagMap(data []byte) (TagMap, error) {
 	return m, nil
 }
 
+// LoadAliases parses the embedded YAML and maps every alias listed for a
+// font to that font's name.
+func LoadAliases(data []byte) (map[string]string, error) {
+	var doc fontsYAML
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing fonts.yaml: %w", err)
+	}
+	m := make(map[string]string)
+	for _, e := range doc.Fonts {
+		for _, alias := range e.Aliases {
+			m[alias] = e.Name
+		}
+	}
+	return m, nil
+}
+
 // HasTag reports whether the named font has the given tag.
 // Always returns true when tag is "all".
 func (tm TagMap) HasTag(name, tag string) bool {
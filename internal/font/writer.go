@@ -0,0 +1,152 @@
+package font
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultEndmark ends every glyph row written by an Encoder unless another
+// endmark is configured.
+const DefaultEndmark = '@'
+
+// fallbackEndmarks are tried, in order, for glyphs whose rows end with the
+// configured endmark, so the row content survives being read back by figlet,
+// which strips every trailing endmark.
+var fallbackEndmarks = []rune{'@', '#', '$', '%', '&', '|', '~', '^'}
+
+// An Encoder writes fonts as FIGfont 2 text.
+type Encoder struct {
+	w io.Writer
+
+	// Endmark ends each glyph row, doubled on the last row.
+	Endmark rune
+}
+
+// NewEncoder returns an Encoder writing to w with DefaultEndmark.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w, Endmark: DefaultEndmark}
+}
+
+// Write serializes f to w as FIGfont 2 text with DefaultEndmark. Reading the
+// output back with Parse yields an identical font.
+func Write(w io.Writer, f *Font) error {
+	return NewEncoder(w).Encode(f)
+}
+
+// Encode writes the header, comments, required characters and code-tagged
+// glyphs of f. Header fields are written as the font declares them; the
+// optional print direction, full layout and code tag count are included when
+// the font's header had them.
+func (e *Encoder) Encode(f *Font) error {
+	bw := bufio.NewWriter(e.w)
+	m := f.metadata
+
+	fields := []int{m.height, m.baseline, m.maxLength, m.oldLayout, m.commentLines}
+	optional := []int{m.printDirection, m.fullLayout, m.codeTag}
+	fields = append(fields, optional[:min(max(m.fields-6, 0), len(optional))]...)
+	header := make([]string, len(fields))
+	for i, v := range fields {
+		header[i] = fmt.Sprint(v)
+	}
+	// Reuse the signature as read so a hardblank that is not valid UTF-8,
+	// such as a Latin-1 byte, is written back byte for byte.
+	signature := m.signature
+	if signature == "" {
+		signature = "flf2a" + string(m.hardBlank)
+	}
+	fmt.Fprintf(bw, "%s %s\n", signature, strings.Join(header, " "))
+
+	if m.commentLines > 0 {
+		comments := strings.Split(m.comments, "\n")
+		for i := range m.commentLines {
+			if i < len(comments) {
+				bw.WriteString(comments[i])
+			}
+			bw.WriteByte('\n')
+		}
+	}
+
+	for c := rune(32); c <= 126; c++ {
+		if err := e.glyph(bw, f, c); err != nil {
+			return err
+		}
+	}
+	for _, c := range deutschChars[:f.deutsch] {
+		if err := e.glyph(bw, f, c); err != nil {
+			return err
+		}
+	}
+	for _, tag := range f.tagged {
+		if tag.Comment != "" {
+			fmt.Fprintf(bw, "%d  %s\n", tag.Code, tag.Comment)
+		} else {
+			fmt.Fprintf(bw, "%d\n", tag.Code)
+		}
+		if err := e.glyph(bw, f, tag.Code); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
+
+// glyph writes the rows of one character. A glyph missing from the font is
+// written as blank rows of the font's height.
+func (e *Encoder) glyph(w *bufio.Writer, f *Font, c rune) error {
+	lines := f.glyphs[c].lines
+	if lines == nil {
+		lines = make([]string, f.metadata.height)
+	}
+
+	mark, ok := e.endmarkFor(lines, f.metadata.hardBlank)
+	if !ok {
+		return fmt.Errorf("no usable endmark for character %q: endmark %q and every fallback end a row or are the hardblank", c, e.Endmark)
+	}
+	for i, line := range lines {
+		w.WriteString(line)
+		w.WriteRune(mark)
+		if i == len(lines)-1 {
+			w.WriteRune(mark)
+		}
+		w.WriteByte('\n')
+	}
+	return nil
+}
+
+// GlyphEndmark returns the endmark e writes after the rows of the glyph for
+// c in f: the configured endmark, or a fallback when a row ends with it or
+// it is the font's hardblank.
+func (e *Encoder) GlyphEndmark(f *Font, c rune) rune {
+	mark, _ := e.endmarkFor(f.glyphs[c].lines, f.metadata.hardBlank)
+	return mark
+}
+
+// endmarkFor returns the configured endmark, or a fallback when it cannot
+// end the rows of the glyph: it is blank, the hardblank, or a row ends with
+// it. It reports false when no fallback can either.
+func (e *Encoder) endmarkFor(lines []string, hardblank rune) (rune, bool) {
+	usable := func(mark rune) bool {
+		if mark == hardblank || mark == ' ' || mark == '\n' || mark == '\r' || mark == 0 {
+			return false
+		}
+		for _, line := range lines {
+			if last, _ := utf8.DecodeLastRuneInString(line); last == mark {
+				return false
+			}
+		}
+		return true
+	}
+
+	if usable(e.Endmark) {
+		return e.Endmark, true
+	}
+	for _, mark := range fallbackEndmarks {
+		if usable(mark) {
+			return mark, true
+		}
+	}
+	return e.Endmark, false
+}
@@ -0,0 +1,134 @@
+package font
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/phantompunk/fig/internal/assert"
+)
+
+// assertSameFont compares everything Parse produces except the smush rules,
+// which are derived from the metadata and hold func values.
+func assertSameFont(t *testing.T, got, want *Font) {
+	t.Helper()
+	if !reflect.DeepEqual(got.metadata, want.metadata) {
+		t.Errorf("%s: metadata differs:\ngot  %+v\nwant %+v", want.name, got.metadata, want.metadata)
+	}
+	if !reflect.DeepEqual(got.glyphs, want.glyphs) {
+		t.Errorf("%s: glyphs differ", want.name)
+	}
+	if got.deutsch != want.deutsch || !reflect.DeepEqual(got.tagged, want.tagged) {
+		t.Errorf("%s: code tags differ", want.name)
+	}
+	assert.Equal(t, len(got.rules), len(want.rules))
+}
+
+func TestWrite_RoundTripsBundledFonts(t *testing.T) {
+	names, err := BundledLoader().List()
+	assert.NilError(t, err)
+
+	// Fonts Parse rejects have nothing to round-trip; any other font that
+	// starts failing must not go unnoticed.
+	var rejected []string
+	for _, name := range names {
+		data, _, err := BundledLoader().Load(name)
+		assert.NilError(t, err)
+		want, err := Parse(data, name, "embedded")
+		if err != nil {
+			rejected = append(rejected, name)
+			continue
+		}
+
+		var buf bytes.Buffer
+		if err := Write(&buf, want); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		got, err := Parse(buf.Bytes(), name, "embedded")
+		if err != nil {
+			t.Fatalf("%s: parsing written font: %v", name, err)
+		}
+		assertSameFont(t, got, want)
+	}
+	slices.Sort(rejected)
+	assert.Equal(t, strings.Join(rejected, ", "), "stencil")
+}
+
+func TestWrite_HardblankIsDefaultEndmark(t *testing.T) {
+	// The hardblank '@' cannot end rows, so the writer falls back to '#'.
+	var b strings.Builder
+	b.WriteString("flf2a@ 1 1 4 0 0\n")
+	for c := 32; c <= 126; c++ {
+		fmt.Fprintf(&b, "%c@##\n", c)
+	}
+	want := Must(Parse([]byte(b.String()), "at", "test"))
+	assert.Equal(t, want.Hardblank(), '@')
+
+	var buf bytes.Buffer
+	assert.NilError(t, Write(&buf, want))
+	assert.Equal(t, strings.Split(buf.String(), "\n")[1+'A'-32], "A@##")
+	assertSameFont(t, Must(Parse(buf.Bytes(), "at", "test")), want)
+
+	// Nor can a blank endmark.
+	buf.Reset()
+	enc := NewEncoder(&buf)
+	enc.Endmark = ' '
+	assert.NilError(t, enc.Encode(want))
+	assertSameFont(t, Must(Parse(buf.Bytes(), "at", "test")), want)
+}
+
+func TestEncoder_Endmark(t *testing.T) {
+	f := Must(Parse(minimalFLF(), "mini", "test"))
+	f.glyphs['A'] = Glyph{lines: []string{"A#"}, width: 2}
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	enc.Endmark = '#'
+	assert.NilError(t, enc.Encode(f))
+
+	lines := strings.Split(buf.String(), "\n")
+	assert.Equal(t, lines[0], "flf2a$ 1 1 2 0 0")
+	assert.Equal(t, lines[1], " ##")
+	// 'A' ends with '#', so it falls back to the first usable endmark.
+	assert.Equal(t, lines[1+'A'-32], "A#@@")
+	assert.Equal(t, enc.GlyphEndmark(f, 'A'), '@')
+	assert.Equal(t, enc.GlyphEndmark(f, 'B'), '#')
+
+	got := Must(Parse(buf.Bytes(), "mini", "test"))
+	assertSameFont(t, got, f)
+
+	// The hardblank '$' cannot be the endmark either.
+	enc.Endmark = '$'
+	assert.Equal(t, enc.GlyphEndmark(f, 'B'), '@')
+
+	// A glyph whose rows end in the endmark and every fallback has none.
+	rows := make([]string, len(fallbackEndmarks)+1)
+	for i, mark := range append([]rune{'*'}, fallbackEndmarks...) {
+		rows[i] = "x" + string(mark)
+	}
+	tall := Blank("tall", len(rows))
+	tall.SetGlyph('A', rows)
+	enc.Endmark = '*'
+	assert.True(t, enc.Encode(tall) != nil)
+}
+
+func TestWrite_KeepsCommentsAndCodeTags(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("flf2a$ 1 1 2 -1 2 0 0 2\nfirst\n\n")
+	for range 95 + 7 {
+		b.WriteString(" @@\n")
+	}
+	b.WriteString("0x263A  WHITE SMILING FACE\n:)@@\n300\nx@@\n")
+	want := Must(Parse([]byte(b.String()), "tagged", "test"))
+
+	var buf bytes.Buffer
+	assert.NilError(t, Write(&buf, want))
+	assert.True(t, strings.HasPrefix(buf.String(), "flf2a$ 1 1 2 -1 2 0 0 2\nfirst\n\n"))
+	assert.True(t, strings.HasSuffix(buf.String(), "9786  WHITE SMILING FACE\n:)@@\n300\nx@@\n"))
+
+	got := Must(Parse(buf.Bytes(), "tagged", "test"))
+	assertSameFont(t, got, want)
+}
@@ -0,0 +1,46 @@
+package graphics
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+)
+
+// kittyChunkSize is the maximum payload per escape sequence allowed by the
+// kitty graphics protocol.
+const kittyChunkSize = 4096
+
+// EncodeKitty writes img using the kitty graphics protocol: the image is PNG
+// encoded, base64 wrapped and transmitted-and-displayed (a=T) in chunks.
+func EncodeKitty(w io.Writer, img image.Image) error {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return fmt.Errorf("encoding png: %w", err)
+	}
+	payload := base64.StdEncoding.EncodeToString(buf.Bytes())
+
+	for first := true; first || len(payload) > 0; first = false {
+		n := min(len(payload), kittyChunkSize)
+		chunk := payload[:n]
+		payload = payload[n:]
+
+		more := 0
+		if len(payload) > 0 {
+			more = 1
+		}
+
+		var err error
+		if first {
+			_, err = fmt.Fprintf(w, "\x1b_Ga=T,f=100,m=%d;%s\x1b\\", more, chunk)
+		} else {
+			_, err = fmt.Fprintf(w, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
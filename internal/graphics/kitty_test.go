@@ -0,0 +1,83 @@
+package graphics
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var kittyChunk = regexp.MustCompile(`\x1b_G([^;]*);([^\x1b]*)\x1b\\`)
+
+func TestEncodeKitty_roundTrip(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	img.Set(1, 1, color.RGBA{R: 0xff, A: 0xff})
+
+	var buf bytes.Buffer
+	if err := EncodeKitty(&buf, img); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	chunks := kittyChunk.FindAllStringSubmatch(buf.String(), -1)
+	if len(chunks) != 1 {
+		t.Fatalf("want 1 chunk for a tiny image, got %d", len(chunks))
+	}
+	if chunks[0][1] != "a=T,f=100,m=0" {
+		t.Errorf("unexpected control keys %q", chunks[0][1])
+	}
+
+	data, err := base64.StdEncoding.DecodeString(chunks[0][2])
+	if err != nil {
+		t.Fatalf("payload is not base64: %v", err)
+	}
+	decoded, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("payload is not png: %v", err)
+	}
+	if r, _, _, _ := decoded.At(1, 1).RGBA(); r != 0xffff {
+		t.Errorf("pixel color not preserved")
+	}
+}
+
+func TestEncodeKitty_chunking(t *testing.T) {
+	// Noise defeats PNG compression so the payload spans several chunks.
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	seed := uint32(1)
+	for i := range img.Pix {
+		seed = seed*1664525 + 1013904223
+		img.Pix[i] = byte(seed >> 24)
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeKitty(&buf, img); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	chunks := kittyChunk.FindAllStringSubmatch(buf.String(), -1)
+	if len(chunks) < 2 {
+		t.Fatalf("expected multiple chunks, got %d", len(chunks))
+	}
+	var payload strings.Builder
+	for i, c := range chunks {
+		last := i == len(chunks)-1
+		switch {
+		case i == 0 && c[1] != "a=T,f=100,m=1":
+			t.Errorf("first chunk keys %q", c[1])
+		case i > 0 && !last && c[1] != "m=1":
+			t.Errorf("chunk %d keys %q", i, c[1])
+		case last && c[1] != "m=0":
+			t.Errorf("last chunk keys %q", c[1])
+		}
+		if len(c[2]) > kittyChunkSize {
+			t.Errorf("chunk %d exceeds %d bytes", i, kittyChunkSize)
+		}
+		payload.WriteString(c[2])
+	}
+	if _, err := base64.StdEncoding.DecodeString(payload.String()); err != nil {
+		t.Errorf("joined payload is not base64: %v", err)
+	}
+}
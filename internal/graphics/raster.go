@@ -0,0 +1,207 @@
+package graphics
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strconv"
+	"strings"
+)
+
+// Options controls how rendered text is rasterized.
+type Options struct {
+	CellWidth  int         // pixels per terminal column
+	CellHeight int         // pixels per terminal row
+	Foreground color.Color // ink color
+	Background color.Color // nil leaves the background transparent
+}
+
+// DefaultOptions returns a 10x20 cell with white ink on a transparent background.
+func DefaultOptions() Options {
+	return Options{
+		CellWidth:  10,
+		CellHeight: 20,
+		Foreground: color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+	}
+}
+
+// Rasterize paints lines onto an image, one cell per rune. Block and shade
+// elements keep their partial coverage, line-drawing characters become
+// strokes and every other visible character fills its cell, so FIGlet output
+// keeps its shape at any cell size.
+func Rasterize(lines []string, opts Options) *image.RGBA {
+	if opts.CellWidth <= 0 || opts.CellHeight <= 0 {
+		def := DefaultOptions()
+		opts.CellWidth, opts.CellHeight = def.CellWidth, def.CellHeight
+	}
+	if opts.Foreground == nil {
+		opts.Foreground = DefaultOptions().Foreground
+	}
+
+	cols := 0
+	for _, l := range lines {
+		cols = max(cols, len([]rune(l)))
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, cols*opts.CellWidth, len(lines)*opts.CellHeight))
+	if opts.Background != nil {
+		fill(img, img.Bounds(), opts.Background)
+	}
+
+	fg := color.RGBAModel.Convert(opts.Foreground).(color.RGBA)
+	for row, l := range lines {
+		for col, ch := range []rune(l) {
+			cell := image.Rect(col*opts.CellWidth, row*opts.CellHeight, (col+1)*opts.CellWidth, (row+1)*opts.CellHeight)
+			paintCell(img, cell, ch, fg, opts.Background)
+		}
+	}
+	return img
+}
+
+// ParseColor parses a "#rrggbb" or "#rgb" hex color.
+func ParseColor(s string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 {
+		return color.RGBA{}, fmt.Errorf("invalid color %q: expected #rrggbb", s)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid color %q: %w", s, err)
+	}
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, nil
+}
+
+// ParseCellSize parses a "WxH" cell size such as "10x20".
+func ParseCellSize(s string) (int, int, error) {
+	w, h, ok := strings.Cut(strings.ToLower(s), "x")
+	if !ok {
+		return 0, 0, fmt.Errorf("invalid cell size %q: expected WxH", s)
+	}
+	width, err := strconv.Atoi(w)
+	if err != nil || width <= 0 {
+		return 0, 0, fmt.Errorf("invalid cell width in %q", s)
+	}
+	height, err := strconv.Atoi(h)
+	if err != nil || height <= 0 {
+		return 0, 0, fmt.Errorf("invalid cell height in %q", s)
+	}
+	return width, height, nil
+}
+
+// paintCell draws a single character into the cell rectangle.
+func paintCell(img *image.RGBA, cell image.Rectangle, ch rune, fg color.RGBA, bg color.Color) {
+	w, h := cell.Dx(), cell.Dy()
+	sub := func(x0, y0, x1, y1 int) image.Rectangle {
+		return image.Rect(cell.Min.X+x0, cell.Min.Y+y0, cell.Min.X+x1, cell.Min.Y+y1)
+	}
+	// Stroke thickness scales with the cell but never drops below a pixel.
+	tx, ty := max(w/5, 1), max(h/8, 1)
+
+	switch ch {
+	case 0, ' ':
+		return
+	case '█':
+		fill(img, cell, fg)
+	case '▀':
+		fill(img, sub(0, 0, w, h/2), fg)
+	case '▄':
+		fill(img, sub(0, h/2, w, h), fg)
+	case '▌':
+		fill(img, sub(0, 0, w/2, h), fg)
+	case '▐':
+		fill(img, sub(w/2, 0, w, h), fg)
+	case '▘':
+		fill(img, sub(0, 0, w/2, h/2), fg)
+	case '▝':
+		fill(img, sub(w/2, 0, w, h/2), fg)
+	case '▖':
+		fill(img, sub(0, h/2, w/2, h), fg)
+	case '▗':
+		fill(img, sub(w/2, h/2, w, h), fg)
+	case '░':
+		shade(img, cell, fg, bg, 0.25)
+	case '▒':
+		shade(img, cell, fg, bg, 0.5)
+	case '▓':
+		shade(img, cell, fg, bg, 0.75)
+	case '_':
+		fill(img, sub(0, h-ty, w, h), fg)
+	case '-', '─', '━', '~':
+		fill(img, sub(0, (h-ty)/2, w, (h+ty)/2), fg)
+	case '=', '═':
+		fill(img, sub(0, h/3-ty/2, w, h/3+(ty+1)/2), fg)
+		fill(img, sub(0, 2*h/3-ty/2, w, 2*h/3+(ty+1)/2), fg)
+	case '|', '│', '┃', '║':
+		fill(img, sub((w-tx)/2, 0, (w+tx)/2, h), fg)
+	case '+', '┼':
+		fill(img, sub(0, (h-ty)/2, w, (h+ty)/2), fg)
+		fill(img, sub((w-tx)/2, 0, (w+tx)/2, h), fg)
+	case '/':
+		diagonal(img, cell, tx, fg, true)
+	case '\\':
+		diagonal(img, cell, tx, fg, false)
+	case '.', ',':
+		fill(img, sub((w-tx)/2, h-2*ty, (w+tx)/2, h-ty), fg)
+	case '\'', '`':
+		fill(img, sub((w-tx)/2, 0, (w+tx)/2, h/4), fg)
+	default:
+		fill(img, cell, fg)
+	}
+}
+
+// diagonal draws a stroke from corner to corner; rising goes bottom-left to
+// top-right like '/'.
+func diagonal(img *image.RGBA, cell image.Rectangle, thickness int, c color.Color, rising bool) {
+	w, h := cell.Dx(), cell.Dy()
+	for y := range h {
+		x := (h - 1 - y) * w / h
+		if !rising {
+			x = y * w / h
+		}
+		start := max(x-thickness/2, 0)
+		end := min(start+thickness, w)
+		fill(img, image.Rect(cell.Min.X+start, cell.Min.Y+y, cell.Min.X+end, cell.Min.Y+y+1), c)
+	}
+}
+
+// bayer is a 4x4 ordered dither matrix; a pixel is inked when its entry is
+// below coverage*16.
+var bayer = [4][4]int{
+	{0, 8, 2, 10},
+	{12, 4, 14, 6},
+	{3, 11, 1, 9},
+	{15, 7, 13, 5},
+}
+
+// shade fills cell with fg at the given coverage. Over a background the two
+// colors are blended. A transparent background is dithered instead, inking
+// that share of pixels solidly like a terminal draws the shade glyphs: sixel
+// has no partial transparency, so a faint ink would vanish.
+func shade(img *image.RGBA, cell image.Rectangle, fg color.RGBA, bg color.Color, coverage float64) {
+	if bg != nil {
+		b := color.RGBAModel.Convert(bg).(color.RGBA)
+		mix := func(f, b uint8) uint8 { return uint8(float64(f)*coverage + float64(b)*(1-coverage)) }
+		fill(img, cell, color.RGBA{R: mix(fg.R, b.R), G: mix(fg.G, b.G), B: mix(fg.B, b.B), A: 0xff})
+		return
+	}
+	r := cell.Intersect(img.Bounds())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if float64(bayer[y%4][x%4]) < coverage*16 {
+				img.Set(x, y, fg)
+			}
+		}
+	}
+}
+
+func fill(img *image.RGBA, r image.Rectangle, c color.Color) {
+	r = r.Intersect(img.Bounds())
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.Set(x, y, c)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package graphics
+
+import (
+	"image/color"
+	"testing"
+
+	"github.com/phantompunk/fig/internal/assert"
+)
+
+func TestRasterize_size(t *testing.T) {
+	img := Rasterize([]string{"##", "#"}, Options{CellWidth: 4, CellHeight: 6})
+
+	assert.Equal(t, img.Bounds().Dx(), 8)
+	assert.Equal(t, img.Bounds().Dy(), 12)
+}
+
+func TestRasterize_blocksAndSpaces(t *testing.T) {
+	red := color.RGBA{R: 0xff, A: 0xff}
+	img := Rasterize([]string{"█ ▀"}, Options{CellWidth: 2, CellHeight: 4, Foreground: red})
+
+	// Full block: every pixel of the first cell is ink.
+	assert.Equal(t, img.RGBAAt(0, 0), red)
+	assert.Equal(t, img.RGBAAt(1, 3), red)
+	// Space: transparent.
+	assert.Equal(t, img.RGBAAt(2, 0).A, uint8(0))
+	// Upper half block: ink on top, transparent below.
+	assert.Equal(t, img.RGBAAt(4, 0), red)
+	assert.Equal(t, img.RGBAAt(4, 3).A, uint8(0))
+}
+
+func TestRasterize_background(t *testing.T) {
+	bg := color.RGBA{B: 0xff, A: 0xff}
+	img := Rasterize([]string{" "}, Options{CellWidth: 2, CellHeight: 2, Background: bg})
+
+	assert.Equal(t, img.RGBAAt(1, 1), bg)
+}
+
+func TestParseColor(t *testing.T) {
+	c, err := ParseColor("#ff8000")
+	assert.NilError(t, err)
+	assert.Equal(t, c, color.RGBA{R: 0xff, G: 0x80, A: 0xff})
+
+	c, err = ParseColor("0f0")
+	assert.NilError(t, err)
+	assert.Equal(t, c, color.RGBA{G: 0xff, A: 0xff})
+
+	_, err = ParseColor("#12345")
+	assert.NotNil(t, err)
+}
+
+func TestParseCellSize(t *testing.T) {
+	w, h, err := ParseCellSize("8x16")
+	assert.NilError(t, err)
+	assert.Equal(t, w, 8)
+	assert.Equal(t, h, 16)
+
+	for _, bad := range []string{"8", "0x16", "ax16", "8x-1"} {
+		if _, _, err := ParseCellSize(bad); err == nil {
+			t.Errorf("ParseCellSize(%q): expected error", bad)
+		}
+	}
+}
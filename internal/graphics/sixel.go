@@ -0,0 +1,137 @@
+package graphics
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"io"
+)
+
+// EncodeSixel writes img as a DEC Sixel image. Opaque pixels are mapped onto
+// a palette of at most 256 registers (exact colors when the image has few
+// enough, otherwise the Plan 9 palette); transparent pixels are left unpainted
+// so the terminal background shows through.
+func EncodeSixel(w io.Writer, img image.Image) error {
+	bw := bufio.NewWriter(w)
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+
+	pal, index := sixelPalette(img)
+
+	// DCS P1;P2;P3 q — aspect ratio 1:1 (P1=0 with raster attributes) and
+	// P2=1 so unset pixels keep the background.
+	fmt.Fprintf(bw, "\x1bP0;1;0q\"1;1;%d;%d", width, height)
+	for i, c := range pal {
+		r, g, bl, _ := c.RGBA()
+		fmt.Fprintf(bw, "#%d;2;%d;%d;%d", i, pct(r), pct(g), pct(bl))
+	}
+
+	for band := 0; band < height; band += 6 {
+		// Collect, per palette register, the sixel column bits for this band.
+		bits := make([][]byte, len(pal))
+		for dy := 0; dy < 6 && band+dy < height; dy++ {
+			for x := range width {
+				i, ok := index(img.At(b.Min.X+x, b.Min.Y+band+dy))
+				if !ok {
+					continue
+				}
+				if bits[i] == nil {
+					bits[i] = make([]byte, width)
+				}
+				bits[i][x] |= 1 << dy
+			}
+		}
+
+		first := true
+		for i, row := range bits {
+			if row == nil {
+				continue
+			}
+			if !first {
+				bw.WriteByte('$') // carriage return within the band
+			}
+			first = false
+			fmt.Fprintf(bw, "#%d", i)
+			writeSixelRow(bw, row)
+		}
+		bw.WriteByte('-') // next band
+	}
+
+	bw.WriteString("\x1b\\")
+	return bw.Flush()
+}
+
+// writeSixelRow writes one register's band using run-length encoding.
+func writeSixelRow(w *bufio.Writer, row []byte) {
+	for x := 0; x < len(row); {
+		run := 1
+		for x+run < len(row) && row[x+run] == row[x] {
+			run++
+		}
+		ch := byte(63 + row[x])
+		if run > 3 {
+			fmt.Fprintf(w, "!%d%c", run, ch)
+		} else {
+			for range run {
+				w.WriteByte(ch)
+			}
+		}
+		x += run
+	}
+}
+
+// sixelPalette returns the palette for img and a lookup that maps a pixel to
+// its register, reporting false for transparent pixels.
+func sixelPalette(img image.Image) (color.Palette, func(color.Color) (int, bool)) {
+	b := img.Bounds()
+	seen := make(map[color.RGBA]int)
+	var exact color.Palette
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := opaque(img.At(x, y))
+			if c.A == 0 {
+				continue
+			}
+			if _, ok := seen[c]; !ok && len(seen) <= 256 {
+				seen[c] = len(exact)
+				exact = append(exact, c)
+			}
+		}
+	}
+
+	if len(seen) <= 256 {
+		return exact, func(c color.Color) (int, bool) {
+			o := opaque(c)
+			if o.A == 0 {
+				return 0, false
+			}
+			return seen[o], true
+		}
+	}
+
+	pal := color.Palette(palette.Plan9)
+	return pal, func(c color.Color) (int, bool) {
+		o := opaque(c)
+		if o.A == 0 {
+			return 0, false
+		}
+		return pal.Index(o), true
+	}
+}
+
+// opaque un-premultiplies c and drops alpha, treating anything below half
+// coverage as fully transparent.
+func opaque(c color.Color) color.RGBA {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	if n.A < 0x80 {
+		return color.RGBA{}
+	}
+	return color.RGBA{R: n.R, G: n.G, B: n.B, A: 0xff}
+}
+
+// pct converts a 16-bit color channel to the 0–100 range sixel uses.
+func pct(v uint32) int {
+	return int((v*100 + 0x7fff) / 0xffff)
+}
@@ -0,0 +1,101 @@
+package graphics
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestEncodeSixel_singleColor(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 5, 6))
+	red := color.RGBA{R: 0xff, A: 0xff}
+	for y := range 6 {
+		for x := range 5 {
+			img.Set(x, y, red)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeSixel(&buf, img); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	// Header, one red register, one band of five full columns ('~' = all six
+	// bits set) run-length encoded, then the string terminator.
+	want := "\x1bP0;1;0q\"1;1;5;6#0;2;100;0;0#0!5~-\x1b\\"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+}
+
+func TestEncodeSixel_transparentPixelsSkipped(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 3, 1))
+	img.Set(1, 0, color.RGBA{G: 0xff, A: 0xff})
+
+	var buf bytes.Buffer
+	if err := EncodeSixel(&buf, img); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+
+	// Only the middle column carries the top bit ('@' = 63+1).
+	if !strings.Contains(buf.String(), "#0?@?-") {
+		t.Errorf("unexpected band data in %q", buf.String())
+	}
+	if strings.Count(buf.String(), ";2;") != 1 {
+		t.Errorf("expected a single palette register in %q", buf.String())
+	}
+}
+
+func TestEncodeSixel_multipleBands(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 1, 7))
+	for y := range 7 {
+		img.Set(0, y, color.RGBA{B: 0xff, A: 0xff})
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeSixel(&buf, img); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	if got := strings.Count(buf.String(), "-"); got != 2 {
+		t.Errorf("want 2 bands for 7 rows, got %d in %q", got, buf.String())
+	}
+}
+
+func TestEncodeSixel_shadeGlyphs(t *testing.T) {
+	red := color.RGBA{R: 0xff, A: 0xff}
+	img := Rasterize([]string{"░▒"}, Options{CellWidth: 4, CellHeight: 6, Foreground: red})
+
+	// With no background the shades are dithered into solid ink, a quarter
+	// and a half of each cell, rather than drawn faint and dropped by sixel.
+	inked := func(x0 int) int {
+		n := 0
+		for y := range 6 {
+			for x := x0; x < x0+4; x++ {
+				if img.RGBAAt(x, y) == red {
+					n++
+				}
+			}
+		}
+		return n
+	}
+	if got := inked(0); got != 6 {
+		t.Errorf("░ inked %d of 24 pixels, want 6", got)
+	}
+	if got := inked(4); got != 12 {
+		t.Errorf("▒ inked %d of 24 pixels, want 12", got)
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeSixel(&buf, img); err != nil {
+		t.Fatalf("encode: %v", err)
+	}
+	// One red register. ░ inks rows 0, 2 and 4 of every other column ('T' is
+	// 63+0b010101); ▒ inks every column, alternating those rows with rows 1,
+	// 3 and 5 ('i' is 63+0b101010).
+	want := "\x1bP0;1;0q\"1;1;8;6#0;2;100;0;0#0T?T?TiTi-\x1b\\"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q\nwant %q", got, want)
+	}
+}
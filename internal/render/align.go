@@ -3,6 +3,7 @@ package render
 import (
 	"os"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/x/term"
 )
@@ -16,6 +17,23 @@ const (
 	AlignRight                   // right-align each line within the terminal width
 )
 
+// String returns the lowercase name of the alignment.
+func (a Alignment) String() string {
+	switch a {
+	case AlignCenter:
+		return "center"
+	case AlignRight:
+		return "right"
+	default:
+		return "left"
+	}
+}
+
+// MarshalText encodes the alignment by name so it reads naturally in JSON.
+func (a Alignment) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
 // alignOutput pads every line in s (a newline-terminated string from
 // canvas.String) so the block sits at the requested alignment within width
 // columns. Lines are padded relative to the widest line in the block so
@@ -29,26 +47,17 @@ func alignOutput(s string, align Alignment, width int) string {
 	trimmed := strings.TrimRight(s, "\n")
 	lines := strings.Split(trimmed, "\n")
 
-	// Content width = widest line in the block.
+	// Content width = widest line in the block, in runes like Layout counts
+	// them, so TOIlet and half-block glyphs are not measured in bytes.
 	contentWidth := 0
 	for _, l := range lines {
-		if len(l) > contentWidth {
-			contentWidth = len(l)
-		}
+		contentWidth = max(contentWidth, utf8.RuneCountInString(l))
 	}
 
+	pad := alignPadding(align, contentWidth, width)
+
 	var sb strings.Builder
 	for _, l := range lines {
-		pad := 0
-		switch align {
-		case AlignCenter:
-			pad = (width - contentWidth) / 2
-		case AlignRight:
-			pad = width - contentWidth
-		}
-		if pad < 0 {
-			pad = 0
-		}
 		sb.WriteString(strings.Repeat(" ", pad))
 		sb.WriteString(l)
 		sb.WriteByte('\n')
@@ -56,6 +65,22 @@ func alignOutput(s string, align Alignment, width int) string {
 	return sb.String()
 }
 
+// alignPadding returns the number of leading spaces needed to place a block
+// of contentWidth columns at the requested alignment within width columns.
+func alignPadding(align Alignment, contentWidth, width int) int {
+	pad := 0
+	switch align {
+	case AlignCenter:
+		pad = (width - contentWidth) / 2
+	case AlignRight:
+		pad = width - contentWidth
+	}
+	if pad < 0 {
+		pad = 0
+	}
+	return pad
+}
+
 // terminalWidth returns the current terminal column count, falling back to 80
 // when stdout is not a TTY (pipes, CI, tests).
 func terminalWidth() int {
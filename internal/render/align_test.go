@@ -120,3 +120,11 @@ func TestAlignOutput_multiline_uniformPad(t *testing.T) {
 		}
 	}
 }
+
+func TestAlignOutput_multibyte_countsRunes(t *testing.T) {
+	// "▀▄█" is three columns but nine bytes.
+	got := alignOutput("▀▄█\n", AlignRight, 10)
+	if want := strings.Repeat(" ", 7) + "▀▄█\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
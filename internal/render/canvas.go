@@ -96,7 +96,10 @@ func (c *Canvas) FindOverlap(glyph [][]rune, maxOverlap int, rules []font.SmushR
 	return overlap
 }
 
-func (c *Canvas) StampSmush(glyph [][]rune, overlap int, rules []font.SmushRule, hb rune) {
+// StampSmush places glyph against the right edge of the existing content,
+// smushing the overlap columns together. It returns the canvas column at which
+// the glyph's first column was placed.
+func (c *Canvas) StampSmush(glyph [][]rune, overlap int, rules []font.SmushRule, hb rune) int {
 	// Find the rightmost non-zero column across all rows.
 	rightEdge := 0
 	for _, row := range c.cells {
@@ -112,7 +115,7 @@ func (c *Canvas) StampSmush(glyph [][]rune, overlap int, rules []font.SmushRule,
 
 	if overlap == 0 {
 		c.Stamp(glyph, rightEdge)
-		return
+		return rightEdge
 	}
 
 	startX := rightEdge - overlap
@@ -135,7 +138,7 @@ func (c *Canvas) StampSmush(glyph [][]rune, overlap int, rules []font.SmushRule,
 			if _, ok := smushCell(left, right, rules, hb); !ok {
 				// Smush failed — fall back to placing glyph right after existing content.
 				c.Stamp(glyph, rightEdge)
-				return
+				return rightEdge
 			}
 		}
 	}
@@ -163,6 +166,7 @@ func (c *Canvas) StampSmush(glyph [][]rune, overlap int, rules []font.SmushRule,
 			}
 		}
 	}
+	return startX
 }
 
 func (c *Canvas) String(hb rune, minWidth int) string {
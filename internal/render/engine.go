@@ -1,6 +1,7 @@
 package render
 
 import (
+	"strings"
 	"sync"
 
 	"github.com/phantompunk/fig/internal/font"
@@ -13,6 +14,7 @@ type RenderOptions struct {
 	FilterFunc []FilterFunc
 	Align      Alignment // AlignLeft (default), AlignCenter, or AlignRight
 	Width      int       // terminal width override; 0 means detect at render time
+	Controls   []string  // FIGlet control files applied to the text, in order
 }
 
 // renderCacheKey is the unique identity of a rendered output. FilterFunc is
@@ -23,6 +25,7 @@ type renderCacheKey struct {
 	fontName string
 	align    Alignment
 	width    int
+	controls string
 }
 
 type Engine struct {
@@ -48,6 +51,24 @@ func NewEngine() *Engine {
 	}
 }
 
+// SetFontLimits sets the limits fonts are held to when the engine loads
+// them. Call it before rendering; see font.FontRegistry.SetLimits.
+func (e *Engine) SetFontLimits(limits font.Limits) {
+	e.registry.SetLimits(limits)
+}
+
+// SetFontAliases makes each alias load the font named by the canonical
+// name it maps to; see font.FontRegistry.SetAliases.
+func (e *Engine) SetFontAliases(aliases map[string]string) {
+	e.registry.SetAliases(aliases)
+}
+
+// FontDuplicates loads every available font and returns the pairs that
+// render alike; see font.FindDuplicates.
+func (e *Engine) FontDuplicates(threshold float64) ([]font.Duplicate, error) {
+	return e.registry.Duplicates(threshold)
+}
+
 // CacheLen returns the number of entries currently in the render cache.
 // Intended for testing and diagnostics.
 func (e *Engine) CacheLen() int {
@@ -66,7 +87,13 @@ func (e *Engine) Render(text string, opts RenderOptions) (string, error) {
 
 	// FilterFunc values are not comparable, so skip the cache when any are set.
 	if len(opts.FilterFunc) == 0 {
-		key := renderCacheKey{text: text, fontName: opts.FontName, align: opts.Align, width: effectiveWidth}
+		key := renderCacheKey{
+			text:     text,
+			fontName: opts.FontName,
+			align:    opts.Align,
+			width:    effectiveWidth,
+			controls: strings.Join(opts.Controls, "\x00"),
+		}
 		e.cacheMu.RLock()
 		if result, ok := e.cache[key]; ok {
 			e.cacheMu.RUnlock()
@@ -93,36 +120,86 @@ func (e *Engine) render(text string, opts RenderOptions, effectiveWidth int) (st
 		return "", err
 	}
 
-	runes := []rune(text)
-	canvas := NewCanvas(f.Height(), len(runes)*f.MaxLength())
+	runes, err := e.input(text, opts.Controls)
+	if err != nil {
+		return "", err
+	}
+
+	canvas, _, cursor := draw(f, runes)
+
+	out := canvas.String(f.Hardblank(), cursor)
+	if opts.Align != AlignLeft {
+		out = alignOutput(out, opts.Align, effectiveWidth)
+	}
+	return out, nil
+}
+
+// RenderFont renders text with f directly, bypassing the engine's registry
+// and cache, for fonts that are not loaded from a file such as one being
+// edited.
+func RenderFont(f *font.Font, text string) string {
+	canvas, _, cursor := draw(f, []rune(text))
+	return canvas.String(f.Hardblank(), cursor)
+}
+
+// input returns the character codes to render for text, after running it
+// through the named control files.
+func (e *Engine) input(text string, controls []string) ([]rune, error) {
+	if len(controls) == 0 {
+		return []rune(text), nil
+	}
+	chain := make([]*font.Control, len(controls))
+	for i, name := range controls {
+		c, err := e.registry.Control(name)
+		if err != nil {
+			return nil, err
+		}
+		chain[i] = c
+	}
+	return font.Chain(chain...).Apply(text), nil
+}
+
+// draw lays runes out on a fresh canvas using the font's layout rules. It
+// returns the canvas, the column span each rune was stamped at, and the
+// full-width cursor used as the minimum output width.
+func draw(f *font.Font, runes []rune) (*Canvas, []Span, int) {
+	// Size the canvas from the glyphs themselves: no layout places a glyph
+	// past the sum of the widths before it, and the header's max length,
+	// which a font may set far above its widest glyph, would cost that much
+	// per rune.
+	glyphs := make([][][]rune, len(runes))
+	width := 0
+	for i, char := range runes {
+		glyphs[i] = f.GlyphRunes(char)
+		width += glyphWidth(glyphs[i])
+	}
+	canvas := NewCanvas(f.Height(), width)
+	spans := make([]Span, 0, len(runes))
 
 	rules := f.Rules()
 	hb := f.Hardblank()
 
 	cursor := 0
 	for i, char := range runes {
-		glyph := f.GlyphRunes(char)
+		glyph := glyphs[i]
 		w := glyphWidth(glyph)
+		start := 0
 		if i == 0 {
 			leftEdge := glyphLeftEdge(glyph)
 			canvas.Stamp(glyph, -leftEdge)
 			cursor = w - leftEdge
-			continue
-		}
-		if f.IsFullWidth() {
+			start = -leftEdge
+		} else if f.IsFullWidth() {
 			canvas.Stamp(glyph, cursor)
+			start = cursor
 			cursor += w
 		} else {
 			overlap := canvas.FindOverlap(glyph, f.MaxLength(), rules, hb)
-			canvas.StampSmush(glyph, overlap, rules, hb)
+			start = canvas.StampSmush(glyph, overlap, rules, hb)
 		}
+		spans = append(spans, Span{Char: string(char), Index: i, Start: max(start, 0), End: max(start+w, 0)})
 	}
-
-	out := canvas.String(hb, cursor)
-	if opts.Align != AlignLeft {
-		out = alignOutput(out, opts.Align, effectiveWidth)
-	}
-	return out, nil
+	return canvas, spans, cursor
 }
 
 // ListFonts returns the names of all fonts available across the engine's loaders.
@@ -130,14 +207,25 @@ func (e *Engine) ListFonts() ([]string, error) {
 	return e.registry.Available()
 }
 
-// FontHeight returns the line height of the named font. The font is loaded and
-// cached on first call; subsequent calls are served from the registry cache.
+// Font returns the named font, loading and caching it on first use.
+func (e *Engine) Font(name string) (*font.Font, error) {
+	return e.registry.Get(name)
+}
+
+// FontHeight returns the line height of the named font. Indexed fonts, such
+// as the bundled ones, are not parsed; see font.FontRegistry.Info.
 func (e *Engine) FontHeight(name string) (int, error) {
-	f, err := e.registry.Get(name)
+	info, err := e.registry.Info(name)
 	if err != nil {
 		return 0, err
 	}
-	return f.Height(), nil
+	return info.Height, nil
+}
+
+// FontInfo describes the named font, from an index when one has it; see
+// font.FontRegistry.Info.
+func (e *Engine) FontInfo(name string) (font.FontInfo, error) {
+	return e.registry.Info(name)
 }
 
 // glyphWidth returns the natural width of a glyph (max row length, including
@@ -1,7 +1,10 @@
 package render
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -16,7 +19,7 @@ type stubFontLoader struct {
 func (s *stubFontLoader) Load(name string) ([]byte, font.Format, error) {
 	data, ok := s.fonts[name]
 	if !ok {
-		return nil, font.FormatFLF, fmt.Errorf("font %q not found", name)
+		return nil, font.FormatFLF, &font.NotFoundError{Name: name}
 	}
 	return data, font.FormatFLF, nil
 }
@@ -61,8 +64,29 @@ func TestEngine_Render_unknownFont(t *testing.T) {
 	e := newEngineWithStub(map[string][]byte{})
 
 	_, err := e.Render("hi", RenderOptions{FontName: "missing"})
-	if err == nil {
-		t.Error("expected error for unknown font, got nil")
+	if !errors.Is(err, font.ErrFontNotFound) {
+		t.Errorf("got %v, want ErrFontNotFound", err)
+	}
+}
+
+func TestEngine_Render_typedErrors(t *testing.T) {
+	e := newEngineWithStub(map[string][]byte{
+		"short": minimalEngineFLF()[:60],
+		"png":   []byte("\x89PNG\r\n\x1a\n"),
+	})
+
+	_, err := e.Render("hi", RenderOptions{FontName: "short"})
+	var perr *font.ParseError
+	if !errors.As(err, &perr) {
+		t.Fatalf("got %v, want a ParseError", err)
+	}
+	if perr.Char == 0 {
+		t.Errorf("ParseError.Char = 0, want the character being read")
+	}
+
+	_, err = e.Render("hi", RenderOptions{FontName: "png"})
+	if !errors.Is(err, font.ErrUnsupportedFormat) {
+		t.Errorf("got %v, want ErrUnsupportedFormat", err)
 	}
 }
 
@@ -172,3 +196,85 @@ func TestEngine_Render_width0_resolvedPerCall(t *testing.T) {
 		t.Errorf("expected 2 cache entries for two distinct resolved widths, got %d", e.CacheLen())
 	}
 }
+
+// identityFLF builds a 1-row full-width FLF where every printable ASCII glyph
+// is the character itself, so output spells out the looked-up characters.
+func identityFLF() []byte {
+	var b strings.Builder
+	b.WriteString("flf2a$ 1 1 2 -1 0\n")
+	for c := 32; c <= 126; c++ {
+		if c == '@' {
+			b.WriteString("@##\n")
+			continue
+		}
+		fmt.Fprintf(&b, "%c@@\n", c)
+	}
+	return []byte(b.String())
+}
+
+func TestEngine_Render_controls(t *testing.T) {
+	dir := t.TempDir()
+	upper := filepath.Join(dir, "upper.flc")
+	if err := os.WriteFile(upper, []byte("flc2a\nt a-z A-Z\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	e := New(font.FileLoader{}, &stubFontLoader{fonts: map[string][]byte{"id": identityFLF()}})
+
+	plain, err := e.Render("fig", RenderOptions{FontName: "id"})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	got, err := e.Render("fig", RenderOptions{FontName: "id", Controls: []string{upper}})
+	if err != nil {
+		t.Fatalf("render with control: %v", err)
+	}
+	if plain != "fig\n" || got != "FIG\n" {
+		t.Errorf("got %q and %q, want %q and %q", plain, got, "fig\n", "FIG\n")
+	}
+	if e.CacheLen() != 2 {
+		t.Errorf("expected separate cache entries with and without controls, got %d", e.CacheLen())
+	}
+
+	if _, err := e.Render("fig", RenderOptions{FontName: "id", Controls: []string{"missing"}}); err == nil {
+		t.Error("expected error for a missing control file")
+	}
+}
+
+func TestRenderFont_editedFont(t *testing.T) {
+	e := newEngineWithStub(map[string][]byte{"id": identityFLF()})
+	f, err := e.Font("id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := e.Render("fig", RenderOptions{FontName: "id"})
+	if got := RenderFont(f, "fig"); got != want {
+		t.Errorf("RenderFont = %q, Render = %q", got, want)
+	}
+
+	edited := f.Clone()
+	edited.SetGlyph('i', []string{"!"})
+	if got := RenderFont(edited, "fig"); got != "f!g\n" {
+		t.Errorf("edited font rendered %q, want %q", got, "f!g\n")
+	}
+	if got, _ := e.Render("fig", RenderOptions{FontName: "id"}); got != want {
+		t.Errorf("editing a clone changed the registry's font: %q", got)
+	}
+}
+
+func TestEngine_Render_glyphsWiderThanMaxLength(t *testing.T) {
+	// The header claims glyphs at most 2 wide, but every glyph is 3 wide.
+	var b strings.Builder
+	b.WriteString("flf2a$ 1 1 2 -1 0\n")
+	for c := 32; c <= 126; c++ {
+		fmt.Fprintf(&b, "%s\x01\x01\n", strings.Repeat(string(rune(c)), 3))
+	}
+	e := newEngineWithStub(map[string][]byte{"wide": []byte(b.String())})
+
+	got, err := e.Render("fig", RenderOptions{FontName: "wide"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "fffiiiggg\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,84 @@
+package render
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Layout is a rendered banner together with the metadata tooling needs to
+// consume it without parsing whitespace: the font geometry, the final output
+// lines and the output columns covered by every source character.
+type Layout struct {
+	Font      string    `json:"font"`
+	Height    int       `json:"height"`
+	Baseline  int       `json:"baseline"`
+	MaxLength int       `json:"maxLength"`
+	Width     int       `json:"width"`
+	Align     Alignment `json:"align"`
+	Lines     []string  `json:"lines"`
+	Spans     []Span    `json:"spans"`
+}
+
+// Span records the output columns [Start, End) occupied by the glyph of a
+// single source character. Index is the character's rune offset in the text.
+// Spans of smushed neighbours overlap by the number of shared columns.
+type Span struct {
+	Char  string `json:"char"`
+	Index int    `json:"index"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// Layout renders text like Render but returns the output lines alongside the
+// font metrics and per-character column spans. Layouts are not cached.
+func (e *Engine) Layout(text string, opts RenderOptions) (*Layout, error) {
+	f, err := e.registry.Get(opts.FontName)
+	if err != nil {
+		return nil, err
+	}
+
+	effectiveWidth := opts.Width
+	if opts.Align != AlignLeft && effectiveWidth == 0 {
+		effectiveWidth = e.TermWidth()
+	}
+
+	runes, err := e.input(text, opts.Controls)
+	if err != nil {
+		return nil, err
+	}
+
+	canvas, spans, cursor := draw(f, runes)
+
+	out := canvas.String(f.Hardblank(), cursor)
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+
+	contentWidth := 0
+	for _, l := range lines {
+		contentWidth = max(contentWidth, utf8.RuneCountInString(l))
+	}
+
+	pad := 0
+	if opts.Align != AlignLeft && effectiveWidth > 0 {
+		pad = alignPadding(opts.Align, contentWidth, effectiveWidth)
+		prefix := strings.Repeat(" ", pad)
+		for i, l := range lines {
+			lines[i] = prefix + l
+		}
+	}
+
+	for i := range spans {
+		spans[i].Start = min(spans[i].Start, contentWidth) + pad
+		spans[i].End = min(spans[i].End, contentWidth) + pad
+	}
+
+	return &Layout{
+		Font:      opts.FontName,
+		Height:    f.Height(),
+		Baseline:  f.Baseline(),
+		MaxLength: f.MaxLength(),
+		Width:     contentWidth + pad,
+		Align:     opts.Align,
+		Lines:     lines,
+		Spans:     spans,
+	}, nil
+}
@@ -0,0 +1,87 @@
+package render
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestLayout_spansFollowGlyphs(t *testing.T) {
+	e := newAlignEngine(20)
+
+	l, err := e.Layout("Hi!", RenderOptions{FontName: "solid"})
+	if err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+
+	if l.Width != 3 || l.Height != 1 {
+		t.Errorf("want width 3 height 1, got width %d height %d", l.Width, l.Height)
+	}
+	if len(l.Lines) != 1 || l.Lines[0] != "###" {
+		t.Errorf("unexpected lines %q", l.Lines)
+	}
+	for i, s := range l.Spans {
+		if s.Index != i || s.Start != i || s.End != i+1 {
+			t.Errorf("span %d: got %+v", i, s)
+		}
+	}
+}
+
+func TestLayout_alignShiftsSpans(t *testing.T) {
+	e := newAlignEngine(20)
+
+	l, err := e.Layout("Hi", RenderOptions{FontName: "solid", Align: AlignCenter, Width: 20})
+	if err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+
+	// "Hi" renders as "##" (2 chars). Width=20 → pad = (20-2)/2 = 9.
+	if l.Spans[0].Start != 9 || l.Spans[1].End != 11 {
+		t.Errorf("spans not shifted by alignment padding: %+v", l.Spans)
+	}
+	if !strings.HasPrefix(l.Lines[0], strings.Repeat(" ", 9)+"#") {
+		t.Errorf("line not padded: %q", l.Lines[0])
+	}
+}
+
+func TestLayout_matchesRender(t *testing.T) {
+	e := New(&stubFontLoader{fonts: map[string][]byte{"solid": solidFLF()}})
+
+	out, err := e.Render("Hello", RenderOptions{FontName: "solid"})
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	l, err := e.Layout("Hello", RenderOptions{FontName: "solid"})
+	if err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	if got := strings.Join(l.Lines, "\n") + "\n"; got != out {
+		t.Errorf("layout lines %q differ from render output %q", got, out)
+	}
+}
+
+func TestLayout_jsonFields(t *testing.T) {
+	e := newAlignEngine(20)
+
+	l, err := e.Layout("Hi", RenderOptions{FontName: "solid", Align: AlignRight, Width: 20})
+	if err != nil {
+		t.Fatalf("layout: %v", err)
+	}
+	data, err := json.Marshal(l)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	for _, key := range []string{"font", "height", "baseline", "maxLength", "width", "align", "lines", "spans"} {
+		if _, ok := got[key]; !ok {
+			t.Errorf("missing %q in %s", key, data)
+		}
+	}
+	if got["align"] != "right" {
+		t.Errorf("align should marshal by name, got %v", got["align"])
+	}
+}
@@ -0,0 +1,388 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"charm.land/bubbles/v2/textinput"
+	tea "charm.land/bubbletea/v2"
+	gloss "charm.land/lipgloss/v2"
+	"github.com/phantompunk/fig/internal/font"
+	"github.com/phantompunk/fig/internal/render"
+)
+
+type designFocus int
+
+const (
+	focusGrid designFocus = iota
+	focusSample
+	focusPick
+)
+
+var (
+	dimStyle       = gloss.NewStyle().Foreground(gloss.Color("#626784"))
+	brightStyle    = gloss.NewStyle().Foreground(gloss.Color("#C4C7D4")).Bold(true)
+	hardblankStyle = gloss.NewStyle().Foreground(gloss.Color("#E5C07B")).Bold(true)
+	cursorStyle    = gloss.NewStyle().Reverse(true)
+)
+
+// designModel edits the glyphs of one font. The glyph under edit is kept as
+// a grid of cells and written back to the font after every change, so the
+// preview always shows the font as it would be saved.
+type designModel struct {
+	font      *font.Font
+	path      string
+	chars     []rune // characters offered by the picker, ascending
+	index     int    // selected character in chars
+	grid      [][]rune
+	row, col  int
+	sample    textinput.Model
+	focus     designFocus
+	dirty     bool
+	quitArmed bool
+	msg       string
+	width     int
+	height    int
+}
+
+func newDesignModel(f *font.Font, path, sample string) *designModel {
+	input := textinput.New()
+	input.Prompt = ":"
+	input.SetValue(sample)
+
+	m := &designModel{
+		font:   f,
+		path:   path,
+		chars:  f.Runes(),
+		sample: input,
+	}
+	m.index = max(slices.Index(m.chars, 'A'), 0)
+	m.load()
+	return m
+}
+
+func (m designModel) Init() tea.Cmd { return nil }
+
+// char returns the character being edited.
+func (m designModel) char() rune { return m.chars[m.index] }
+
+// load fills the grid from the selected character's glyph, padding rows to
+// the glyph's width.
+func (m *designModel) load() {
+	rows, _ := m.font.Glyph(m.char())
+	if len(rows) < m.font.Height() {
+		rows = append(rows, make([]string, m.font.Height()-len(rows))...)
+	}
+	width := 0
+	m.grid = make([][]rune, len(rows))
+	for i, row := range rows {
+		m.grid[i] = []rune(row)
+		width = max(width, len(m.grid[i]))
+	}
+	m.resize(max(width, 1))
+	m.row = min(m.row, len(m.grid)-1)
+	m.col = min(m.col, width)
+}
+
+// resize pads or cuts every row of the grid to width cells.
+func (m *designModel) resize(width int) {
+	for i, row := range m.grid {
+		if len(row) < width {
+			row = append(row, []rune(strings.Repeat(" ", width-len(row)))...)
+		}
+		m.grid[i] = row[:width]
+	}
+}
+
+func (m *designModel) gridWidth() int {
+	if len(m.grid) == 0 {
+		return 0
+	}
+	return len(m.grid[0])
+}
+
+// commit writes the grid back to the font.
+func (m *designModel) commit() {
+	rows := make([]string, len(m.grid))
+	for i, row := range m.grid {
+		rows[i] = string(row)
+	}
+	m.font.SetGlyph(m.char(), rows)
+	m.dirty = true
+}
+
+// set puts r in the cell under the cursor and moves right, widening the
+// glyph when the cursor is at its right edge.
+func (m *designModel) set(r rune) {
+	if m.col >= m.gridWidth() {
+		m.resize(m.col + 1)
+	}
+	m.grid[m.row][m.col] = r
+	m.col++
+	m.commit()
+}
+
+// selectChar switches the grid to c, adding it to the picker when the font
+// does not have it yet.
+func (m *designModel) selectChar(c rune) {
+	i, found := slices.BinarySearch(m.chars, c)
+	if !found {
+		m.chars = slices.Insert(m.chars, i, c)
+	}
+	m.index = i
+	m.load()
+}
+
+func (m *designModel) save() {
+	file, err := os.Create(m.path)
+	if err == nil {
+		err = font.Write(file, m.font)
+		if cerr := file.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if err != nil {
+		m.msg = "save failed: " + err.Error()
+		return
+	}
+	m.dirty = false
+	m.msg = "saved " + m.path
+}
+
+func (m designModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		return m, nil
+
+	case tea.KeyPressMsg:
+		key := msg.String()
+		if key != "esc" && key != "ctrl+c" {
+			m.quitArmed = false
+		}
+		m.msg = ""
+
+		switch m.focus {
+		case focusSample:
+			if key == "enter" || key == "esc" {
+				m.focus = focusGrid
+				m.sample.Blur()
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.sample, cmd = m.sample.Update(msg)
+			return m, cmd
+
+		case focusPick:
+			m.focus = focusGrid
+			if r := []rune(msg.Text); len(r) == 1 {
+				m.selectChar(r[0])
+			}
+			return m, nil
+		}
+
+		switch key {
+		case "esc", "ctrl+c", "ctrl+q":
+			if m.dirty && !m.quitArmed {
+				m.quitArmed = true
+				m.msg = "unsaved changes: ctrl+s saves, esc again quits"
+				return m, nil
+			}
+			return m, tea.Quit
+		case "ctrl+s":
+			m.save()
+		case "ctrl+e":
+			m.focus = focusSample
+			return m, m.sample.Focus()
+		case "ctrl+p":
+			m.focus = focusPick
+			m.msg = "type the character to edit"
+		case "tab", "pgdown":
+			m.index = (m.index + 1) % len(m.chars)
+			m.load()
+		case "shift+tab", "pgup":
+			m.index = (m.index - 1 + len(m.chars)) % len(m.chars)
+			m.load()
+		case "up":
+			m.row = max(m.row-1, 0)
+		case "down":
+			m.row = min(m.row+1, len(m.grid)-1)
+		case "left":
+			m.col = max(m.col-1, 0)
+		case "right":
+			m.col = min(m.col+1, m.gridWidth())
+		case "home":
+			m.col = 0
+		case "end":
+			m.col = m.gridWidth()
+		case "ctrl+right":
+			m.resize(m.gridWidth() + 1)
+			m.commit()
+		case "ctrl+left":
+			if m.gridWidth() > 0 {
+				m.resize(m.gridWidth() - 1)
+				m.col = min(m.col, m.gridWidth())
+				m.commit()
+			}
+		case "ctrl+b":
+			m.set(m.font.Hardblank())
+		case "backspace":
+			if m.col > 0 {
+				m.col--
+				m.grid[m.row][m.col] = ' '
+				m.commit()
+			}
+		case "delete":
+			if m.col < m.gridWidth() {
+				m.grid[m.row][m.col] = ' '
+				m.commit()
+			}
+		case "enter":
+			m.row = min(m.row+1, len(m.grid)-1)
+			m.col = 0
+		default:
+			if r := []rune(msg.Text); len(r) == 1 && msg.Mod&(tea.ModCtrl|tea.ModAlt) == 0 {
+				m.set(r[0])
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m designModel) View() tea.View {
+	parts := []string{
+		m.titleBar(),
+		m.pickerView(),
+		"",
+		m.gridView(),
+		"",
+		m.previewView(),
+	}
+	content := gloss.JoinVertical(gloss.Left, parts...)
+
+	footerHeight := 2
+	body := gloss.NewStyle().Height(max(m.height-footerHeight, 0)).MaxHeight(max(m.height-footerHeight, 0)).Render(content)
+	v := tea.NewView(gloss.JoinVertical(gloss.Left, body, m.designStatus(), m.designHelp()))
+	v.AltScreen = true
+	return v
+}
+
+func (m designModel) titleBar() string {
+	title := brightStyle.Render("fig design") + "   " + m.font.Name() + dimStyle.Render(" → "+m.path)
+	if m.dirty {
+		title += hardblankStyle.Render("  [modified]")
+	}
+	underline := dimStyle.Render(strings.Repeat("‾", max(m.width, 1)))
+	return gloss.JoinVertical(gloss.Left, gloss.NewStyle().Padding(0, 1).Render(title), underline)
+}
+
+// pickerView shows the characters around the selected one, which is
+// highlighted, and its code.
+func (m designModel) pickerView() string {
+	c := m.char()
+	label := fmt.Sprintf("%q U+%04X (%d/%d)  ", c, c, m.index+1, len(m.chars))
+	span := max((m.width-len(label)-4)/6, 2) // cells are 3 columns wide
+	start := max(m.index-span, 0)
+	end := min(start+2*span+1, len(m.chars))
+	start = max(end-2*span-1, 0)
+
+	var b strings.Builder
+	for i := start; i < end; i++ {
+		cell := " " + printable(m.chars[i]) + " "
+		if i == m.index {
+			b.WriteString(cursorStyle.Render(cell))
+		} else {
+			b.WriteString(dimStyle.Render(cell))
+		}
+	}
+	return gloss.NewStyle().Padding(0, 1).Render(brightStyle.Render(label) + b.String())
+}
+
+// gridView draws the glyph cell by cell. Hardblanks are highlighted, blank
+// cells are dots, and each row ends with the endmarks it will be saved with.
+func (m designModel) gridView() string {
+	hb := m.font.Hardblank()
+	// The grid is committed after every change, so the font's glyph is the
+	// one that will be saved.
+	mark := string(font.NewEncoder(io.Discard).GlyphEndmark(m.font, m.char()))
+	lines := make([]string, len(m.grid))
+	for i, row := range m.grid {
+		var b strings.Builder
+		b.WriteString(dimStyle.Render("│"))
+		for j, r := range row {
+			cell, style := string(r), gloss.NewStyle()
+			switch r {
+			case ' ':
+				cell, style = "·", dimStyle
+			case hb:
+				style = hardblankStyle
+			}
+			if i == m.row && j == m.col && m.focus == focusGrid {
+				style = cursorStyle
+			}
+			b.WriteString(style.Render(cell))
+		}
+		if i == m.row && m.col == len(row) && m.focus == focusGrid {
+			b.WriteString(cursorStyle.Render(" "))
+		}
+		end := mark
+		if i == len(m.grid)-1 {
+			end += mark
+		}
+		b.WriteString(dimStyle.Render(end))
+		lines[i] = b.String()
+	}
+	return gloss.NewStyle().Padding(0, 2).Render(strings.Join(lines, "\n"))
+}
+
+// previewView renders the sample text with the font as edited.
+func (m designModel) previewView() string {
+	text := m.sample.Value()
+	if text == "" {
+		text = m.font.Name()
+	}
+	label := dimStyle.Render("sample")
+	if m.focus == focusSample {
+		label = brightStyle.Render("sample")
+	}
+	preview := render.RenderFont(m.font, text)
+	return gloss.JoinVertical(gloss.Left,
+		gloss.NewStyle().Padding(0, 1).Render(label+m.sample.View()),
+		gloss.NewStyle().Padding(0, 4).Render(preview),
+	)
+}
+
+func (m designModel) designStatus() string {
+	if m.msg != "" {
+		return dimStyle.Render(" " + m.msg)
+	}
+	return dimStyle.Render(fmt.Sprintf(" hardblank %s   row %d col %d   %dx%d",
+		hardblankStyle.Render(string(m.font.Hardblank())), m.row+1, m.col+1, m.gridWidth(), len(m.grid)))
+}
+
+func (m designModel) designHelp() string {
+	controls := "type to draw   ←↑↓→ move   ^b hardblank   ^←/^→ width   tab/⇧tab char   ^p pick   ^e sample   ^s save   esc quit"
+	if m.focus == focusSample {
+		controls = "Enter apply   Esc done"
+	}
+	return dimStyle.Padding(0, 1).Render(controls)
+}
+
+// printable returns c as it can be shown in the picker.
+func printable(c rune) string {
+	if c == ' ' {
+		return "␠"
+	}
+	return string(c)
+}
+
+// Design runs the glyph editor on f, saving to path.
+func Design(f *font.Font, path, sample string) error {
+	if _, err := tea.NewProgram(newDesignModel(f, path, sample)).Run(); err != nil {
+		return fmt.Errorf("running designer: %w", err)
+	}
+	return nil
+}
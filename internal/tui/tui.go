@@ -52,7 +52,7 @@ type model struct {
 	copyMsg       string
 }
 
-func newModel() *model {
+func newModel(engine *render.Engine) *model {
 	textInput := textinput.New()
 	textInput.Prompt = ":"
 	filterInput := textinput.New()
@@ -64,7 +64,7 @@ func newModel() *model {
 	return &model{
 		textInput:   textInput,
 		filterInput: filterInput,
-		engine:      render.New(font.BundledLoader()),
+		engine:      engine,
 		tagMap:      tm,
 		activeTag:   "all",
 	}
@@ -506,8 +506,9 @@ func (m *model) toggleFocusState() tea.Cmd {
 	return nil
 }
 
-func Start() error {
-	m := newModel()
+// Start runs the interactive font browser using engine for rendering.
+func Start(engine *render.Engine) error {
+	m := newModel(engine)
 
 	if _, err := tea.NewProgram(m).Run(); err != nil {
 		return fmt.Errorf("running tui: %w", err)